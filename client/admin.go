@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Breakers returns the state of the server's circuit breakers, one per LinkedIn target
+// and per session cookie. It needs the admin token of the server (WithAdminToken); the
+// error matches ErrUnauthorized without it, and ErrNotFound when the server has none.
+func (c *Client) Breakers(ctx context.Context) ([]BreakerState, error) {
+	var body struct {
+		Breakers []BreakerState `json:"breakers"`
+	}
+	r := c.request(ctx)
+	if c.adminToken != "" {
+		r.SetBearerAuthToken(c.adminToken)
+	}
+	if _, err := send(r, http.MethodGet, "/admin/breakers", &body); err != nil {
+		return nil, err
+	}
+	return body.Breakers, nil
+}
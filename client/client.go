@@ -0,0 +1,156 @@
+// Package client is a Go client for the LinkedIn Enricher API.
+//
+//	c := client.NewClient("http://localhost:3000", client.WithSessionCookie(liAt))
+//	result, err := c.EnrichCompany(ctx, "google", nil)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+const (
+	headerSessionCookie = "X-Linkedin-Session-Cookie"
+	headerProxyURL      = "X-Proxy-Url"
+	headerTimeout       = "X-Request-Timeout"
+)
+
+// maxRequestTimeout is the longest X-Request-Timeout the server accepts.
+const maxRequestTimeout = 10 * time.Minute
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	sessionCookie string
+	proxyURL      string
+	adminToken    string
+	concurrency   int
+	http          *req.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithSessionCookie sends a LinkedIn 'li_at' session cookie with every request, for
+// authenticated scrapes.
+func WithSessionCookie(cookie string) Option {
+	return func(c *Client) { c.sessionCookie = cookie }
+}
+
+// WithProxyURL makes the server scrape LinkedIn through a proxy.
+func WithProxyURL(proxyURL string) Option {
+	return func(c *Client) { c.proxyURL = proxyURL }
+}
+
+// WithAdminToken sets the ADMIN_TOKEN of the server, required by the admin endpoints
+// such as Breakers.
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.adminToken = token }
+}
+
+// WithTimeout limits the duration of each attempt of a request (default 2 minutes).
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.http.SetTimeout(timeout) }
+}
+
+// WithRetries sets how many times a request is retried after a network error or a 429,
+// 502, 503 or 504 response (default 2), backing off between min and max.
+func WithRetries(count int, min, max time.Duration) Option {
+	return func(c *Client) {
+		c.http.SetCommonRetryCount(count).SetCommonRetryBackoffInterval(min, max)
+	}
+}
+
+// WithConcurrency sets how many requests EnrichCompanies sends at once (default 4).
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.http.SetUserAgent(userAgent) }
+}
+
+// NewClient returns a client of the API served at baseURL, e.g. "http://localhost:3000".
+// The "/api/v1" prefix is added when baseURL does not end with it.
+func NewClient(baseURL string, opts ...Option) *Client {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if !strings.HasSuffix(baseURL, "/api/v1") {
+		baseURL += "/api/v1"
+	}
+
+	c := &Client{
+		concurrency: 4,
+		http: req.C().
+			SetBaseURL(baseURL).
+			SetTimeout(2*time.Minute).
+			SetUserAgent("li-enricher-client").
+			SetCommonRetryCount(2).
+			SetCommonRetryBackoffInterval(500*time.Millisecond, 5*time.Second).
+			SetCommonRetryCondition(shouldRetry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// shouldRetry retries network errors, rate limiting and unavailable upstreams, but not
+// cancelled requests.
+func shouldRetry(resp *req.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// request starts a request carrying the context and the LinkedIn headers. The deadline
+// of ctx is sent along, so that the server stops scraping once nobody waits for it,
+// capped at the longest one the server accepts.
+func (c *Client) request(ctx context.Context) *req.Request {
+	r := c.http.R().SetContext(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > 0 {
+			r.SetHeader(headerTimeout, min(remaining, maxRequestTimeout).Round(time.Millisecond).String())
+		}
+	}
+	if c.sessionCookie != "" {
+		r.SetHeader(headerSessionCookie, c.sessionCookie)
+	}
+	if c.proxyURL != "" {
+		r.SetHeader(headerProxyURL, c.proxyURL)
+	}
+	return r
+}
+
+// send sends the request and decodes a successful JSON response into result, when not
+// nil. Error responses are returned as *APIError.
+func send(r *req.Request, method, path string, result interface{}) (*req.Response, error) {
+	resp, err := r.Send(method, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, newAPIError(resp)
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Bytes(), result); err != nil {
+			return nil, fmt.Errorf("%s %s: failed to decode response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
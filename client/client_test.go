@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient serves handler and returns a client of it that retries without waiting.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	opts = append([]Option{WithRetries(2, time.Millisecond, time.Millisecond)}, opts...)
+	return NewClient(server.URL, opts...)
+}
+
+// writeJSON answers with a JSON body.
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func TestNewClientBaseURL(t *testing.T) {
+	for _, suffix := range []string{"", "/", "/api/v1", "/api/v1/"} {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			writeJSON(w, http.StatusOK, `{"valid": true}`)
+		}))
+		c := NewClient(server.URL + suffix)
+		if _, err := c.ValidateCookie(context.Background()); err != nil {
+			t.Fatalf("base URL suffix %q: %v", suffix, err)
+		}
+		server.Close()
+		if path != "/api/v1/validate-cookie" {
+			t.Errorf("base URL suffix %q: requested %q, want /api/v1/validate-cookie", suffix, path)
+		}
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		writeJSON(w, http.StatusOK, `{"valid": true}`)
+	}, WithSessionCookie("li-at"), WithProxyURL("http://proxy:8080"), WithUserAgent("test-agent"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := c.ValidateCookie(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := header.Get(headerSessionCookie); got != "li-at" {
+		t.Errorf("%s = %q, want li-at", headerSessionCookie, got)
+	}
+	if got := header.Get(headerProxyURL); got != "http://proxy:8080" {
+		t.Errorf("%s = %q, want http://proxy:8080", headerProxyURL, got)
+	}
+	if got := header.Get("User-Agent"); got != "test-agent" {
+		t.Errorf("User-Agent = %q, want test-agent", got)
+	}
+	timeout, err := time.ParseDuration(header.Get(headerTimeout))
+	if err != nil || timeout <= 0 || timeout > time.Minute {
+		t.Errorf("%s = %q, want the time left until the deadline", headerTimeout, header.Get(headerTimeout))
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if _, err := c.ValidateCookie(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(headerTimeout); got != maxRequestTimeout.String() {
+		t.Errorf("%s = %q with an hour left, want the server's cap %s", headerTimeout, got, maxRequestTimeout)
+	}
+}
+
+func TestRequestHeadersOmitted(t *testing.T) {
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		writeJSON(w, http.StatusOK, `{"valid": false}`)
+	})
+	if _, err := c.ValidateCookie(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{headerSessionCookie, headerProxyURL, headerTimeout} {
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			t.Errorf("%s sent without being set", name)
+		}
+	}
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	sentinels := []error{ErrBadRequest, ErrNotFound, ErrUnprocessable, ErrRateLimited, ErrServer, ErrUnavailable, ErrTimeout}
+	tests := []struct {
+		status int
+		want   []error
+	}{
+		{http.StatusBadRequest, []error{ErrBadRequest}},
+		{http.StatusNotFound, []error{ErrNotFound}},
+		{http.StatusUnprocessableEntity, []error{ErrUnprocessable}},
+		{http.StatusTooManyRequests, []error{ErrRateLimited}},
+		{http.StatusInternalServerError, []error{ErrServer}},
+		{http.StatusServiceUnavailable, []error{ErrServer, ErrUnavailable}},
+		{http.StatusGatewayTimeout, []error{ErrServer, ErrTimeout}},
+		{http.StatusConflict, nil},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, tt.status, `{"error": "failed", "details": "because"}`)
+		}, WithRetries(0, time.Millisecond, time.Millisecond))
+
+		_, err := c.EnrichCompany(context.Background(), "google", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("status %d: error %v is not an *APIError", tt.status, err)
+		}
+		if apiErr.StatusCode != tt.status || apiErr.Message != "failed" || apiErr.Details != "because" {
+			t.Errorf("status %d: got %+v", tt.status, apiErr)
+		}
+		for _, sentinel := range sentinels {
+			want := false
+			for _, w := range tt.want {
+				want = want || w == sentinel
+			}
+			if got := errors.Is(err, sentinel); got != want {
+				t.Errorf("status %d: errors.Is(err, %v) = %v, want %v", tt.status, sentinel, got, want)
+			}
+		}
+	}
+}
+
+func TestAPIErrorWithoutBody(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not json"))
+	})
+	_, err := c.EnrichCompany(context.Background(), "google", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not an *APIError", err)
+	}
+	if apiErr.Message != "Not Found" {
+		t.Errorf("Message = %q, want the status text", apiErr.Message)
+	}
+	if err.Error() != "404 Not Found" {
+		t.Errorf("Error() = %q, want 404 Not Found", err.Error())
+	}
+}
+
+func TestAPIErrorDiagnostics(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusInternalServerError, `{"error": "failed", "diagnostics": {"strategies": ["guest-html"]}}`)
+	}, WithRetries(0, time.Millisecond, time.Millisecond))
+	_, err := c.EnrichCompany(context.Background(), "google", &EnrichOptions{Debug: true})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not an *APIError", err)
+	}
+	if string(apiErr.Diagnostics) != `{"strategies": ["guest-html"]}` {
+		t.Errorf("Diagnostics = %s", apiErr.Diagnostics)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"scrapeType": `)
+	})
+	_, err := c.EnrichCompany(context.Background(), "google", nil)
+	if err == nil {
+		t.Fatal("no error for a malformed response")
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("malformed response reported as an API error: %v", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		status   int
+		attempts int32
+	}{
+		{http.StatusTooManyRequests, 3},
+		{http.StatusBadGateway, 3},
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusGatewayTimeout, 3},
+		// A scrape or extraction that failed fails again.
+		{http.StatusInternalServerError, 1},
+		{http.StatusBadRequest, 1},
+		{http.StatusNotFound, 1},
+		{http.StatusUnprocessableEntity, 1},
+	}
+	for _, tt := range tests {
+		var attempts atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			writeJSON(w, tt.status, `{"error": "failed"}`)
+		})
+		if _, err := c.EnrichCompany(context.Background(), "google", nil); err == nil {
+			t.Fatalf("status %d: no error", tt.status)
+		}
+		if got := attempts.Load(); got != tt.attempts {
+			t.Errorf("status %d: %d attempts, want %d", tt.status, got, tt.attempts)
+		}
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, `{"error": "circuit breaker open"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"scrapeType": "public", "data": {"name": "Google"}}`)
+	})
+	result, err := c.EnrichCompany(context.Background(), "google", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ScrapeType != "public" || attempts.Load() != 3 {
+		t.Errorf("got %+v after %d attempts", result, attempts.Load())
+	}
+}
+
+func TestWithRetriesDisabled(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		writeJSON(w, http.StatusServiceUnavailable, `{"error": "unavailable"}`)
+	}, WithRetries(0, time.Millisecond, time.Millisecond))
+	if _, err := c.EnrichCompany(context.Background(), "google", nil); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("%d attempts, want 1", got)
+	}
+}
+
+func TestCancelledRequestNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		<-r.Context().Done()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.EnrichCompany(ctx, "google", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("%d attempts, want 1", got)
+	}
+}
+
+func TestBreakers(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/breakers" {
+			t.Errorf("requested %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Authorization = %q, want the admin token", auth)
+		}
+		writeJSON(w, http.StatusOK, `{"breakers": [
+			{"name": "target:company_pages", "state": "open", "requests": 6, "failures": 4,
+			 "opened_at": "2026-07-01T10:00:00Z", "retry_at": "2026-07-01T10:01:00Z", "last_failure": "status 999"},
+			{"name": "target:voyager_api", "state": "closed", "requests": 2}
+		]}`)
+	}, WithAdminToken("secret"))
+	breakers, err := c.Breakers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breakers) != 2 {
+		t.Fatalf("got %d breakers, want 2", len(breakers))
+	}
+	open := breakers[0]
+	if open.State != "open" || open.Failures != 4 || open.LastFailure != "status 999" || open.RetryAt == nil || open.RetryAt.Sub(*open.OpenedAt) != time.Minute {
+		t.Errorf("open breaker = %+v", open)
+	}
+	if closed := breakers[1]; closed.State != "closed" || closed.OpenedAt != nil || closed.Requests != 2 {
+		t.Errorf("closed breaker = %+v", closed)
+	}
+}
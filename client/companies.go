@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnrichOptions tunes EnrichCompany. The zero value returns the summary.
+type EnrichOptions struct {
+	View   string    // "summary" (default), "detailed" or "raw".
+	Fields []string  // Fields to return, nested with dots, e.g. "headquarters.country".
+	Debug  bool      // Attach diagnostics about the fetched page.
+	AsOf   time.Time // Return the stored snapshot valid at that time instead of scraping.
+}
+
+// EnrichCompany scrapes a company. Without a session cookie, the public page is scraped.
+func (c *Client) EnrichCompany(ctx context.Context, slug string, opts *EnrichOptions) (*CompanyResult, error) {
+	r := c.request(ctx).SetPathParam("slug", slug)
+	if opts != nil {
+		if opts.View != "" {
+			r.SetQueryParam("view", opts.View)
+		}
+		if len(opts.Fields) > 0 {
+			r.SetQueryParam("fields", strings.Join(opts.Fields, ","))
+		}
+		if opts.Debug {
+			r.SetQueryParam("debug", "true")
+		}
+		if !opts.AsOf.IsZero() {
+			r.SetQueryParam("as_of", opts.AsOf.UTC().Format(time.RFC3339))
+		}
+	}
+
+	var result CompanyResult
+	if _, err := send(r, http.MethodGet, "/companies/{slug}", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BatchResult is the outcome of one company of EnrichCompanies.
+type BatchResult struct {
+	Slug   string
+	Result *CompanyResult
+	Err    error
+}
+
+// EnrichCompanies enriches several companies concurrently (see WithConcurrency) and
+// returns their outcomes in the order of slugs. A failed company does not stop the
+// others; cancelling ctx does.
+func (c *Client) EnrichCompanies(ctx context.Context, slugs []string, opts *EnrichOptions) []BatchResult {
+	results := make([]BatchResult, len(slugs))
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i, slug := range slugs {
+		results[i].Slug = slug
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, slug string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Result, results[i].Err = c.EnrichCompany(ctx, slug, opts)
+		}(i, slug)
+	}
+	wg.Wait()
+	return results
+}
+
+// SearchCompanies searches companies by name. It requires a session cookie.
+func (c *Client) SearchCompanies(ctx context.Context, query string) ([]SearchResult, error) {
+	var results []SearchResult
+	r := c.request(ctx).SetPathParam("query", query)
+	if _, err := send(r, http.MethodGet, "/companies/search/{query}", &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ValidateCookie reports whether the client's session cookie is valid.
+func (c *Client) ValidateCookie(ctx context.Context) (bool, error) {
+	var body struct {
+		Valid bool `json:"valid"`
+	}
+	if _, err := send(c.request(ctx), http.MethodGet, "/validate-cookie", &body); err != nil {
+		return false, err
+	}
+	return body.Valid, nil
+}
+
+// SimilarCompanies returns the companies LinkedIn lists as similar to a company.
+func (c *Client) SimilarCompanies(ctx context.Context, slug string) ([]SimilarCompany, error) {
+	var body struct {
+		SimilarCompanies []SimilarCompany `json:"similar_companies"`
+	}
+	r := c.request(ctx).SetPathParam("slug", slug)
+	if _, err := send(r, http.MethodGet, "/companies/{slug}/similar", &body); err != nil {
+		return nil, err
+	}
+	return body.SimilarCompanies, nil
+}
+
+// History lists the stored snapshots of a company, newest first. Zero limit lists the
+// server's default page size; HistoryPage reads the following pages.
+func (c *Client) History(ctx context.Context, slug string, limit int) ([]Snapshot, error) {
+	snapshots, _, err := c.HistoryPage(ctx, slug, limit, 0)
+	return snapshots, err
+}
+
+// HistoryPage lists the stored snapshots of a company, newest first, skipping the first
+// offset ones. It also returns the offset of the next page, or zero after the last one.
+func (c *Client) HistoryPage(ctx context.Context, slug string, limit, offset int) ([]Snapshot, int, error) {
+	var body struct {
+		Snapshots  []Snapshot `json:"snapshots"`
+		NextOffset int        `json:"next_offset"`
+	}
+	r := c.request(ctx).SetPathParam("slug", slug)
+	if limit > 0 {
+		r.SetQueryParam("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		r.SetQueryParam("offset", strconv.Itoa(offset))
+	}
+	if _, err := send(r, http.MethodGet, "/companies/{slug}/history", &body); err != nil {
+		return nil, 0, err
+	}
+	return body.Snapshots, body.NextOffset, nil
+}
+
+// ChangesOptions selects the snapshot Changes compares the latest one with. The zero
+// value compares with the previous snapshot.
+type ChangesOptions struct {
+	From    int64     // ID of the snapshot to compare with.
+	Since   time.Time // Compare with the snapshot valid at that time.
+	Refresh bool      // Scrape the company before comparing.
+}
+
+// Changes returns the changes of a company between two snapshots.
+func (c *Client) Changes(ctx context.Context, slug string, opts *ChangesOptions) (*ChangeSet, error) {
+	r := c.request(ctx).SetPathParam("slug", slug)
+	if opts != nil {
+		if opts.From > 0 {
+			r.SetQueryParam("from", strconv.FormatInt(opts.From, 10))
+		}
+		if !opts.Since.IsZero() {
+			r.SetQueryParam("since", opts.Since.UTC().Format(time.RFC3339))
+		}
+		if opts.Refresh {
+			r.SetQueryParam("refresh", "true")
+		}
+	}
+
+	var changes ChangeSet
+	if _, err := send(r, http.MethodGet, "/companies/{slug}/changes", &changes); err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+// ParseOptions tunes ParseHTML.
+type ParseOptions struct {
+	Slug   string // Company the page describes, read from its canonical URL when empty.
+	View   string
+	Fields []string
+}
+
+// ParseHTML extracts the company from a saved LinkedIn page, without the server
+// fetching anything. The result always carries the diagnostics.
+func (c *Client) ParseHTML(ctx context.Context, html []byte, opts *ParseOptions) (*CompanyResult, error) {
+	r := c.request(ctx).
+		SetHeader("Content-Type", "text/html; charset=utf-8").
+		SetBodyBytes(html)
+	if opts != nil {
+		if opts.Slug != "" {
+			r.SetQueryParam("slug", opts.Slug)
+		}
+		if opts.View != "" {
+			r.SetQueryParam("view", opts.View)
+		}
+		if len(opts.Fields) > 0 {
+			r.SetQueryParam("fields", strings.Join(opts.Fields, ","))
+		}
+	}
+
+	var result CompanyResult
+	if _, err := send(r, http.MethodPost, "/parse", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CSVOptions describes the CSV sent to EnrichCSV.
+type CSVOptions struct {
+	Column         string   // Header or 0-based index of the identifier column.
+	IdentifierType string   // "slug", "url", "name" or "domain".
+	Columns        []string // Enrichment columns; all fields when empty.
+}
+
+// CSVResult is an enriched CSV.
+type CSVResult struct {
+	CSV      []byte
+	Enriched int
+	Failed   int
+}
+
+// EnrichCSV enriches every row of a CSV and returns it with the enrichment columns
+// appended. It is not retried, so that a lost response cannot scrape every row again.
+func (c *Client) EnrichCSV(ctx context.Context, csv io.Reader, opts CSVOptions) (*CSVResult, error) {
+	r := c.request(ctx).
+		SetFileReader("file", "companies.csv", csv).
+		SetFormData(map[string]string{
+			"column":          opts.Column,
+			"identifier_type": opts.IdentifierType,
+			"columns":         strings.Join(opts.Columns, ","),
+		}).
+		SetRetryCount(0)
+
+	resp, err := send(r, http.MethodPost, "/companies/enrich-csv", nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &CSVResult{CSV: resp.Bytes()}
+	result.Enriched, _ = strconv.Atoi(resp.GetHeader("X-Rows-Enriched"))
+	result.Failed, _ = strconv.Atoi(resp.GetHeader("X-Rows-Failed"))
+	return result, nil
+}
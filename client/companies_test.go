@@ -0,0 +1,423 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEnrichCompany(t *testing.T) {
+	var request *http.Request
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		writeJSON(w, http.StatusOK, `{
+			"scrapeType": "full",
+			"view": "summary",
+			"parser": {"strategy": "bpr-guid", "version": "2"},
+			"data": {"name": "Google", "founded_year": 1998},
+			"snapshot": {"id": 7, "captured_at": "2026-07-01T10:00:00Z", "scrapeType": "full"},
+			"attempts": 2
+		}`)
+	})
+
+	asOf := time.Date(2026, 7, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	result, err := c.EnrichCompany(context.Background(), "google", &EnrichOptions{
+		View:   "detailed",
+		Fields: []string{"name", "headquarters.country"},
+		Debug:  true,
+		AsOf:   asOf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Method != http.MethodGet || request.URL.Path != "/api/v1/companies/google" {
+		t.Errorf("sent %s %s", request.Method, request.URL.Path)
+	}
+	query := request.URL.Query()
+	want := map[string]string{"view": "detailed", "fields": "name,headquarters.country", "debug": "true", "as_of": "2026-07-01T10:00:00Z"}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("query %s = %q, want %q", key, got, value)
+		}
+	}
+
+	if result.ScrapeType != "full" || result.View != "summary" || result.Parser.Strategy != "bpr-guid" || result.Attempts != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Snapshot == nil || result.Snapshot.ID != 7 || !result.Snapshot.CapturedAt.Equal(time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("snapshot = %+v", result.Snapshot)
+	}
+	var data struct {
+		Name        string `json:"name"`
+		FoundedYear int    `json:"founded_year"`
+	}
+	if err := result.DecodeData(&data); err != nil || data.Name != "Google" || data.FoundedYear != 1998 {
+		t.Errorf("data = %+v, %v", data, err)
+	}
+}
+
+func TestEnrichCompanyWithoutOptions(t *testing.T) {
+	var rawQuery string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		writeJSON(w, http.StatusOK, `{"scrapeType": "public", "data": {}}`)
+	})
+	if _, err := c.EnrichCompany(context.Background(), "google", nil); err != nil {
+		t.Fatal(err)
+	}
+	if rawQuery != "" {
+		t.Errorf("query = %q, want none", rawQuery)
+	}
+}
+
+func TestEnrichCompanies(t *testing.T) {
+	const concurrency = 2
+	var inFlight, maxInFlight atomic.Int32
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if n <= seen || maxInFlight.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		<-release
+
+		slug := strings.TrimPrefix(r.URL.Path, "/api/v1/companies/")
+		switch slug {
+		case "missing":
+			writeJSON(w, http.StatusNotFound, `{"error": "company not found"}`)
+		case "broken":
+			writeJSON(w, http.StatusInternalServerError, `{"error": "failed"}`)
+		default:
+			writeJSON(w, http.StatusOK, `{"scrapeType": "public", "data": {"name": "`+slug+`"}}`)
+		}
+	}, WithConcurrency(concurrency))
+
+	// Let the requests through one by one once the first ones have piled up.
+	go func() {
+		for inFlight.Load() < concurrency {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+	}()
+
+	slugs := []string{"google", "missing", "microsoft", "broken", "apple", "meta"}
+	results := c.EnrichCompanies(context.Background(), slugs, nil)
+
+	if got := maxInFlight.Load(); got != concurrency {
+		t.Errorf("%d requests in flight at once, want %d", got, concurrency)
+	}
+	if len(results) != len(slugs) {
+		t.Fatalf("got %d results, want %d", len(results), len(slugs))
+	}
+	for i, result := range results {
+		if result.Slug != slugs[i] {
+			t.Errorf("result %d is for %q, want %q", i, result.Slug, slugs[i])
+		}
+		switch result.Slug {
+		case "missing":
+			if !errors.Is(result.Err, ErrNotFound) || result.Result != nil {
+				t.Errorf("missing: %+v, want ErrNotFound", result)
+			}
+		case "broken":
+			if !errors.Is(result.Err, ErrServer) || result.Result != nil {
+				t.Errorf("broken: %+v, want ErrServer", result)
+			}
+		default:
+			var data struct{ Name string }
+			if result.Err != nil || result.Result.DecodeData(&data) != nil || data.Name != result.Slug {
+				t.Errorf("%s: %+v", result.Slug, result)
+			}
+		}
+	}
+}
+
+func TestEnrichCompaniesCancelled(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		cancel()
+		<-r.Context().Done()
+	}, WithConcurrency(1))
+
+	results := c.EnrichCompanies(ctx, []string{"google", "microsoft", "apple"}, nil)
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("%s: err = %v, want context.Canceled", result.Slug, result.Err)
+		}
+	}
+	if len(requested) != 1 {
+		t.Errorf("sent %v after the context was cancelled", requested)
+	}
+}
+
+func TestSearchCompanies(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v1/companies/search/data%20platform" {
+			t.Errorf("requested %s", r.URL.EscapedPath())
+		}
+		writeJSON(w, http.StatusOK, `[{"id": "1441", "name": "Google", "text": "Software Development"}]`)
+	}, WithSessionCookie("li-at"))
+
+	results, err := c.SearchCompanies(context.Background(), "data platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0] != (SearchResult{ID: "1441", Name: "Google", Text: "Software Development"}) {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestSearchCompaniesUnauthenticated(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadRequest, `{"error": "Header 'X-Linkedin-Session-Cookie' is required"}`)
+	})
+	if _, err := c.SearchCompanies(context.Background(), "google"); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("err = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestValidateCookie(t *testing.T) {
+	for _, valid := range []bool{true, false} {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if valid {
+				writeJSON(w, http.StatusOK, `{"valid": true}`)
+			} else {
+				writeJSON(w, http.StatusOK, `{"valid": false}`)
+			}
+		})
+		got, err := c.ValidateCookie(context.Background())
+		if err != nil || got != valid {
+			t.Errorf("ValidateCookie() = %v, %v, want %v", got, err, valid)
+		}
+	}
+}
+
+func TestSimilarCompanies(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/companies/google/similar" {
+			t.Errorf("requested %s", r.URL.Path)
+		}
+		writeJSON(w, http.StatusOK, `{"similar_companies": [
+			{"name": "Microsoft", "slug": "microsoft", "industry": "Software Development", "follower_count": 100},
+			{"name": "Apple"}
+		]}`)
+	})
+	similar, err := c.SimilarCompanies(context.Background(), "google")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SimilarCompany{
+		{Name: "Microsoft", Slug: "microsoft", Industry: "Software Development", FollowerCount: 100},
+		{Name: "Apple"},
+	}
+	if len(similar) != len(want) || similar[0] != want[0] || similar[1] != want[1] {
+		t.Errorf("similar = %+v, want %+v", similar, want)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	var limit string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/companies/google/history" {
+			t.Errorf("requested %s", r.URL.Path)
+		}
+		limit = r.URL.Query().Get("limit")
+		writeJSON(w, http.StatusOK, `{"snapshots": [
+			{"id": 2, "slug": "google", "captured_at": "2026-07-02T00:00:00Z", "scrapeType": "full", "parser": {"strategy": "bpr-guid", "version": "2"}},
+			{"id": 1, "slug": "google", "captured_at": "2026-07-01T00:00:00Z", "scrapeType": "public", "parser": {"strategy": "guest-html", "version": "1"}}
+		]}`)
+	})
+
+	snapshots, err := c.History(context.Background(), "google", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != "2" {
+		t.Errorf("limit = %q, want 2", limit)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != 2 || snapshots[1].Parser.Strategy != "guest-html" || snapshots[1].ScrapeType != "public" {
+		t.Errorf("snapshots = %+v", snapshots)
+	}
+
+	if _, err := c.History(context.Background(), "google", 0); err != nil {
+		t.Fatal(err)
+	}
+	if limit != "" {
+		t.Errorf("limit = %q without a limit", limit)
+	}
+}
+
+func TestHistoryPage(t *testing.T) {
+	var query url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, http.StatusOK, `{"snapshots": [
+			{"id": 1, "slug": "google", "captured_at": "2026-07-01T00:00:00Z", "scrapeType": "full"}
+		], "next_offset": 3}`)
+	})
+
+	snapshots, next, err := c.HistoryPage(context.Background(), "google", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("limit") != "1" || query.Get("offset") != "2" {
+		t.Errorf("query = %v, want limit 1 and offset 2", query)
+	}
+	if len(snapshots) != 1 || next != 3 {
+		t.Errorf("snapshots = %+v, next offset = %d", snapshots, next)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, `{"error": "company history is disabled"}`)
+	})
+	if _, err := c.History(context.Background(), "google", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	var request *http.Request
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		writeJSON(w, http.StatusOK, `{
+			"slug": "google",
+			"from": {"id": 1, "captured_at": "2026-07-01T00:00:00Z"},
+			"to": {"id": 3, "captured_at": "2026-07-03T00:00:00Z"},
+			"changes": [{"path": "website", "type": "modified", "old": "google.com", "new": "about.google"}]
+		}`)
+	})
+
+	since := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	changes, err := c.Changes(context.Background(), "google", &ChangesOptions{From: 1, Since: since, Refresh: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.URL.Path != "/api/v1/companies/google/changes" {
+		t.Errorf("requested %s", request.URL.Path)
+	}
+	query := request.URL.Query()
+	if query.Get("from") != "1" || query.Get("since") != "2026-07-01T00:00:00Z" || query.Get("refresh") != "true" {
+		t.Errorf("query = %s", request.URL.RawQuery)
+	}
+	if changes.From.ID != 1 || changes.To.ID != 3 || len(changes.Changes) != 1 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if change := changes.Changes[0]; change.Path != "website" || change.Type != "modified" || change.Old != "google.com" || change.New != "about.google" {
+		t.Errorf("change = %+v", change)
+	}
+
+	if _, err := c.Changes(context.Background(), "google", nil); err != nil {
+		t.Fatal(err)
+	}
+	if request.URL.RawQuery != "" {
+		t.Errorf("query = %q without options", request.URL.RawQuery)
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	const page = "<html><body>saved page</body></html>"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/parse" {
+			t.Errorf("sent %s %s", r.Method, r.URL.Path)
+		}
+		if string(body) != page {
+			t.Errorf("body = %q, want the page", body)
+		}
+		if got := r.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+			t.Errorf("Content-Type = %q", got)
+		}
+		query := r.URL.Query()
+		if query.Get("slug") != "google" || query.Get("view") != "raw" || query.Get("fields") != "name,website" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		writeJSON(w, http.StatusOK, `{"scrapeType": "full", "view": "raw", "data": {}, "diagnostics": {"page_kind": "authenticated"}}`)
+	})
+
+	result, err := c.ParseHTML(context.Background(), []byte(page), &ParseOptions{Slug: "google", View: "raw", Fields: []string{"name", "website"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.View != "raw" || len(result.Diagnostics) == 0 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestParseHTMLUnprocessable(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnprocessableEntity, `{"error": "no company data found", "diagnostics": {}}`)
+	})
+	_, err := c.ParseHTML(context.Background(), []byte("<html></html>"), nil)
+	if !errors.Is(err, ErrUnprocessable) {
+		t.Errorf("err = %v, want ErrUnprocessable", err)
+	}
+}
+
+func TestEnrichCSV(t *testing.T) {
+	const upload = "Company,Website\nGoogle,google.com\n"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/companies/enrich-csv" {
+			t.Errorf("sent %s %s", r.Method, r.URL.Path)
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("no file: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if string(content) != upload {
+			t.Errorf("file = %q", content)
+		}
+		if r.FormValue("column") != "Website" || r.FormValue("identifier_type") != "domain" || r.FormValue("columns") != "name,website" {
+			t.Errorf("form = %v", r.MultipartForm.Value)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("X-Rows-Enriched", "1")
+		w.Header().Set("X-Rows-Failed", "0")
+		w.Write([]byte("Company,Website,li_status\nGoogle,google.com,ok\n"))
+	})
+
+	result, err := c.EnrichCSV(context.Background(), strings.NewReader(upload), CSVOptions{
+		Column:         "Website",
+		IdentifierType: "domain",
+		Columns:        []string{"name", "website"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Enriched != 1 || result.Failed != 0 || !strings.HasSuffix(string(result.CSV), "Google,google.com,ok\n") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestEnrichCSVNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		writeJSON(w, http.StatusGatewayTimeout, `{"error": "Failed to enrich CSV"}`)
+	})
+	_, err := c.EnrichCSV(context.Background(), strings.NewReader("slug\ngoogle\n"), CSVOptions{Column: "slug", IdentifierType: "slug"})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("err = %v, want ErrTimeout", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("%d attempts, want 1", got)
+	}
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/imroc/req/v3"
+)
+
+// Errors matched by *APIError with errors.Is, by HTTP status.
+var (
+	ErrBadRequest    = errors.New("bad request")        // 400: invalid parameters.
+	ErrUnauthorized  = errors.New("unauthorized")       // 401: missing or wrong admin token.
+	ErrNotFound      = errors.New("not found")          // 404: unknown snapshot or watchlist, history or admin endpoints disabled.
+	ErrUnprocessable = errors.New("unprocessable page") // 422: no company data in a parsed page.
+	ErrRateLimited   = errors.New("rate limited")       // 429
+	ErrServer        = errors.New("server error")       // 5xx: the scrape or the extraction failed.
+	ErrUnavailable   = errors.New("unavailable")        // 503: a circuit breaker refused the LinkedIn requests.
+	ErrTimeout       = errors.New("timed out")          // 504: the deadline passed before LinkedIn answered.
+)
+
+// APIError is an error response of the API.
+type APIError struct {
+	StatusCode  int             `json:"-"`
+	Message     string          `json:"error"`
+	Details     string          `json:"details,omitempty"`
+	Diagnostics json.RawMessage `json:"diagnostics,omitempty"` // Set for failed debug scrapes and parses.
+}
+
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Message, e.Details)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel error of the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnprocessable:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusServiceUnavailable
+	case ErrTimeout:
+		return e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+func newAPIError(resp *req.Response) *APIError {
+	apiErr := &APIError{}
+	if err := json.Unmarshal(resp.Bytes(), apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StrategyInfo names the parser strategy and version that produced a result.
+type StrategyInfo struct {
+	Strategy string `json:"strategy"`
+	Version  string `json:"version"`
+}
+
+// SnapshotInfo identifies a stored snapshot.
+type SnapshotInfo struct {
+	ID         int64     `json:"id"`
+	CapturedAt time.Time `json:"captured_at"`
+	ScrapeType string    `json:"scrapeType,omitempty"`
+}
+
+// CompanyResult is the company payload of the enrich and parse endpoints. Data and
+// Diagnostics are kept as JSON; use DecodeData to read the data into a struct or map.
+type CompanyResult struct {
+	ScrapeType  string          `json:"scrapeType"`
+	View        string          `json:"view"`
+	Parser      StrategyInfo    `json:"parser"`
+	Data        json.RawMessage `json:"data"`
+	Diagnostics json.RawMessage `json:"diagnostics,omitempty"`
+	Snapshot    *SnapshotInfo   `json:"snapshot,omitempty"`
+	Attempts    int             `json:"attempts,omitempty"` // LinkedIn requests sent by the server, retries included.
+}
+
+// DecodeData decodes the company data into v.
+func (r *CompanyResult) DecodeData(v interface{}) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+// SearchResult is a company found by SearchCompanies.
+type SearchResult struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// SimilarCompany is a company LinkedIn lists as similar to another.
+type SimilarCompany struct {
+	Name          string `json:"name"`
+	Slug          string `json:"slug,omitempty"`
+	Industry      string `json:"industry,omitempty"`
+	FollowerCount int    `json:"follower_count,omitempty"`
+	LogoURL       string `json:"logo_url,omitempty"`
+}
+
+// Snapshot is a stored enrichment of a company, listed without its data.
+type Snapshot struct {
+	ID         int64        `json:"id"`
+	Slug       string       `json:"slug"`
+	CapturedAt time.Time    `json:"captured_at"`
+	ScrapeType string       `json:"scrapeType"`
+	Parser     StrategyInfo `json:"parser"`
+}
+
+// Change is a field added, removed or modified between two snapshots.
+type Change struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// ChangeSet lists the changes of a company between two snapshots.
+type ChangeSet struct {
+	Slug    string       `json:"slug"`
+	From    SnapshotInfo `json:"from"`
+	To      SnapshotInfo `json:"to"`
+	Changes []Change     `json:"changes"`
+}
+
+// Watchlist is a list of companies re-enriched on a cron schedule.
+type Watchlist struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Slugs         []string  `json:"slugs"`
+	Schedule      string    `json:"schedule"`
+	WebhookURL    string    `json:"webhook_url,omitempty"`
+	Authenticated bool      `json:"authenticated"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// WatchlistRequest creates or replaces a watchlist. The client's session cookie and
+// proxy are stored with it for the scheduled scrapes.
+type WatchlistRequest struct {
+	Name       string   `json:"name"`
+	Slugs      []string `json:"slugs"`
+	Schedule   string   `json:"schedule"`
+	WebhookURL string   `json:"webhook_url,omitempty"`
+}
+
+// RunState is the scheduling state of one company of a watchlist.
+type RunState struct {
+	Slug        string     `json:"slug"`
+	NextRun     time.Time  `json:"next_run"`
+	LastRun     *time.Time `json:"last_run,omitempty"`
+	LastStatus  string     `json:"last_status"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastChanges int        `json:"last_changes"`
+}
+
+// WatchlistSchedule is the next run of every company of a watchlist.
+type WatchlistSchedule struct {
+	WatchlistID int64      `json:"watchlist_id"`
+	Schedule    string     `json:"schedule"`
+	Runs        []RunState `json:"runs"`
+}
+
+// BreakerState is the state of a circuit breaker guarding the LinkedIn requests of the
+// server: "closed", "open" or "half_open".
+type BreakerState struct {
+	Name        string     `json:"name"`
+	State       string     `json:"state"`
+	Requests    int        `json:"requests"`
+	Failures    int        `json:"failures"`
+	OpenedAt    *time.Time `json:"opened_at,omitempty"`
+	RetryAt     *time.Time `json:"retry_at,omitempty"`
+	LastFailure string     `json:"last_failure,omitempty"`
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// CreateWatchlist registers a watchlist. It is not retried, so that a lost response
+// cannot create it twice.
+func (c *Client) CreateWatchlist(ctx context.Context, watchlist WatchlistRequest) (*Watchlist, error) {
+	var created Watchlist
+	r := c.request(ctx).SetBodyJsonMarshal(watchlist).SetRetryCount(0)
+	if _, err := send(r, http.MethodPost, "/watchlists", &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListWatchlists lists the watchlists.
+func (c *Client) ListWatchlists(ctx context.Context) ([]Watchlist, error) {
+	var body struct {
+		Watchlists []Watchlist `json:"watchlists"`
+	}
+	if _, err := send(c.request(ctx), http.MethodGet, "/watchlists", &body); err != nil {
+		return nil, err
+	}
+	return body.Watchlists, nil
+}
+
+// GetWatchlist returns a watchlist.
+func (c *Client) GetWatchlist(ctx context.Context, id int64) (*Watchlist, error) {
+	var watchlist Watchlist
+	r := c.request(ctx).SetPathParam("id", strconv.FormatInt(id, 10))
+	if _, err := send(r, http.MethodGet, "/watchlists/{id}", &watchlist); err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// UpdateWatchlist replaces a watchlist. The stored session cookie and proxy are
+// replaced by the client's when it has them.
+func (c *Client) UpdateWatchlist(ctx context.Context, id int64, watchlist WatchlistRequest) (*Watchlist, error) {
+	var updated Watchlist
+	r := c.request(ctx).SetPathParam("id", strconv.FormatInt(id, 10)).SetBodyJsonMarshal(watchlist)
+	if _, err := send(r, http.MethodPut, "/watchlists/{id}", &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteWatchlist deletes a watchlist.
+func (c *Client) DeleteWatchlist(ctx context.Context, id int64) error {
+	r := c.request(ctx).SetPathParam("id", strconv.FormatInt(id, 10))
+	_, err := send(r, http.MethodDelete, "/watchlists/{id}", nil)
+	return err
+}
+
+// WatchlistSchedule returns the next scheduled run of every company of a watchlist.
+func (c *Client) WatchlistSchedule(ctx context.Context, id int64) (*WatchlistSchedule, error) {
+	var schedule WatchlistSchedule
+	r := c.request(ctx).SetPathParam("id", strconv.FormatInt(id, 10))
+	if _, err := send(r, http.MethodGet, "/watchlists/{id}/schedule", &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+const watchlistJSON = `{
+	"id": 4,
+	"name": "competitors",
+	"slugs": ["google", "microsoft"],
+	"schedule": "@daily",
+	"webhook_url": "https://example.com/hook",
+	"authenticated": true,
+	"created_at": "2026-07-01T00:00:00Z",
+	"updated_at": "2026-07-02T00:00:00Z"
+}`
+
+func TestCreateWatchlist(t *testing.T) {
+	var body WatchlistRequest
+	var header http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/watchlists" {
+			t.Errorf("sent %s %s", r.Method, r.URL.Path)
+		}
+		header = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		writeJSON(w, http.StatusCreated, watchlistJSON)
+	}, WithSessionCookie("li-at"))
+
+	request := WatchlistRequest{Name: "competitors", Slugs: []string{"google", "microsoft"}, Schedule: "@daily", WebhookURL: "https://example.com/hook"}
+	watchlist, err := c.CreateWatchlist(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.Name != request.Name || len(body.Slugs) != 2 || body.Schedule != "@daily" || body.WebhookURL != request.WebhookURL {
+		t.Errorf("sent %+v", body)
+	}
+	if header.Get(headerSessionCookie) != "li-at" {
+		t.Errorf("session cookie not sent for the scheduled scrapes")
+	}
+	if watchlist.ID != 4 || !watchlist.Authenticated || len(watchlist.Slugs) != 2 || watchlist.CreatedAt.IsZero() {
+		t.Errorf("watchlist = %+v", watchlist)
+	}
+}
+
+func TestCreateWatchlistNotRetried(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusBadGateway} {
+		var attempts atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			writeJSON(w, status, `{"error": "unavailable"}`)
+		})
+		_, err := c.CreateWatchlist(context.Background(), WatchlistRequest{Name: "a", Slugs: []string{"google"}, Schedule: "@daily"})
+		if !errors.Is(err, ErrServer) {
+			t.Errorf("status %d: err = %v, want ErrServer", status, err)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("status %d: %d attempts, want 1", status, got)
+		}
+	}
+}
+
+func TestListWatchlists(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/watchlists" {
+			t.Errorf("sent %s %s", r.Method, r.URL.Path)
+		}
+		writeJSON(w, http.StatusOK, `{"watchlists": [`+watchlistJSON+`]}`)
+	})
+	watchlists, err := c.ListWatchlists(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(watchlists) != 1 || watchlists[0].Name != "competitors" {
+		t.Errorf("watchlists = %+v", watchlists)
+	}
+}
+
+func TestGetWatchlist(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/watchlists/4":
+			writeJSON(w, http.StatusOK, watchlistJSON)
+		default:
+			writeJSON(w, http.StatusNotFound, `{"error": "watchlist not found"}`)
+		}
+	})
+	watchlist, err := c.GetWatchlist(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if watchlist.ID != 4 || watchlist.WebhookURL != "https://example.com/hook" {
+		t.Errorf("watchlist = %+v", watchlist)
+	}
+	if _, err := c.GetWatchlist(context.Background(), 5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUpdateWatchlist(t *testing.T) {
+	var body WatchlistRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/watchlists/4" {
+			t.Errorf("sent %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		writeJSON(w, http.StatusOK, watchlistJSON)
+	})
+	updated, err := c.UpdateWatchlist(context.Background(), 4, WatchlistRequest{Name: "competitors", Slugs: []string{"google"}, Schedule: "@weekly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.Schedule != "@weekly" || updated.ID != 4 {
+		t.Errorf("sent %+v, got %+v", body, updated)
+	}
+}
+
+func TestDeleteWatchlist(t *testing.T) {
+	var method, path string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if err := c.DeleteWatchlist(context.Background(), 4); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodDelete || path != "/api/v1/watchlists/4" {
+		t.Errorf("sent %s %s", method, path)
+	}
+}
+
+func TestWatchlistsDisabled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, `{"error": "Watchlists are disabled"}`)
+	})
+	if err := c.DeleteWatchlist(context.Background(), 4); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestWatchlistSchedule(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/watchlists/4/schedule" {
+			t.Errorf("requested %s", r.URL.Path)
+		}
+		writeJSON(w, http.StatusOK, `{"watchlist_id": 4, "schedule": "@daily", "runs": [
+			{"slug": "google", "next_run": "2026-07-03T00:00:00Z", "last_run": "2026-07-02T00:00:00Z", "last_status": "ok", "last_changes": 2},
+			{"slug": "microsoft", "next_run": "2026-07-03T00:00:00Z", "last_status": "pending", "last_changes": 0}
+		]}`)
+	})
+	schedule, err := c.WatchlistSchedule(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.WatchlistID != 4 || len(schedule.Runs) != 2 {
+		t.Fatalf("schedule = %+v", schedule)
+	}
+	if run := schedule.Runs[0]; run.LastRun == nil || run.LastStatus != "ok" || run.LastChanges != 2 {
+		t.Errorf("run = %+v", run)
+	}
+	if run := schedule.Runs[1]; run.LastRun != nil || run.LastStatus != "pending" {
+		t.Errorf("run = %+v", run)
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vit0-9/li-enricher-api/export"
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/server"
+	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// enrichRecord is the output of one enriched company.
+type enrichRecord struct {
+	Slug  string `json:"slug"`
+	Error string `json:"error,omitempty"`
+	*services.CompanyResult
+}
+
+var enrichTableColumns = []string{"slug", "scrapeType", "data.name", "data.website", "data.employee_count_range", "error"}
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: li-enricher %s [flags] %s\n\nFlags:\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs parses flags given before, between or after the positional arguments, and
+// returns the arguments.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// noArgs refuses positional arguments, which the command would otherwise ignore.
+func noArgs(flags *flag.FlagSet, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	fmt.Fprintf(flags.Output(), "unexpected arguments: %s\n", strings.Join(args, " "))
+	flags.Usage()
+	return flag.ErrHelp
+}
+
+func runEnrich(ctx context.Context, args []string) error {
+	var opts options
+	flags := newFlagSet("enrich", "<slug...>")
+	opts.register(flags)
+	view := flags.String("view", services.ViewSummary, "payload view: summary, detailed or raw")
+	fields := flags.String("fields", "", "comma separated fields to return, nested with dots")
+	debug := flags.Bool("debug", false, "include diagnostics about the fetched pages")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := opts.resolve(); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	if !services.IsValidView(*view) {
+		return fmt.Errorf("view must be one of summary, detailed, raw")
+	}
+
+	enrichOpts := services.EnrichOptions{
+		View:   *view,
+		Debug:  *debug,
+		Fields: utils.ParseFieldSet(*fields),
+	}
+	// An interrupted run still writes the companies enriched so far.
+	records, failed, runErr := enrichAll(ctx, args, opts, enrichOpts)
+
+	format := firstNonEmpty(opts.format, formatJSON)
+	var data interface{} = records
+	if len(records) == 1 && format == formatJSON {
+		data = records[0]
+	}
+	if err := write(os.Stdout, format, data, export.ParseColumns(opts.columns), enrichTableColumns); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return runErr
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func runSearch(ctx context.Context, args []string) error {
+	var opts options
+	flags := newFlagSet("search", "<query>")
+	opts.register(flags)
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := opts.resolve(); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	results, err := opts.enricher().SearchCompanies(ctx, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	return write(os.Stdout, firstNonEmpty(opts.format, formatJSON), results, export.ParseColumns(opts.columns), []string{"id", "name", "text"})
+}
+
+func runValidateCookie(ctx context.Context, args []string) error {
+	var opts options
+	flags := newFlagSet("validate-cookie", "")
+	opts.register(flags)
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	if err := opts.resolve(); err != nil {
+		return err
+	}
+	valid, err := opts.enricher().ValidateSession(ctx)
+	if err != nil {
+		return err
+	}
+	if err := write(os.Stdout, firstNonEmpty(opts.format, formatJSON), map[string]bool{"valid": valid}, nil, []string{"valid"}); err != nil {
+		return err
+	}
+	if !valid {
+		return errFailed
+	}
+	return nil
+}
+
+func runBatch(ctx context.Context, args []string) error {
+	var opts options
+	flags := newFlagSet("batch", "")
+	opts.register(flags)
+	input := flags.String("input", "", "file of companies: one slug per line, or a CSV with --column ('-' for stdin)")
+	output := flags.String("output", "", "file to write the results to (default stdout); its extension sets the default format")
+	column := flags.String("column", "", "header or 0-based index of the identifier column of a CSV input")
+	identifierType := flags.String("identifier-type", services.IdentifierSlug, "identifiers of the CSV column: slug, url, name or domain")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	if err := opts.resolve(); err != nil {
+		return err
+	}
+	if *input == "" {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	in, err := openInput(*input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out := io.Writer(os.Stdout)
+	if *output != "" && *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *column != "" {
+		report, err := opts.enricher().EnrichCSV(ctx, in, out, services.CSVEnrichOptions{
+			Column:         *column,
+			IdentifierType: *identifierType,
+			Columns:        export.ParseColumns(opts.columns),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%d rows, %d enriched, %d failed\n", report.Rows, report.Enriched, report.Failed)
+		if report.Failed > 0 {
+			return errFailed
+		}
+		return nil
+	}
+
+	slugs, err := readSlugs(in)
+	if err != nil {
+		return err
+	}
+	records, failed, runErr := enrichAll(ctx, slugs, opts, services.EnrichOptions{View: services.ViewSummary})
+	format := firstNonEmpty(opts.format, formatFromExtension(*output), formatJSON)
+	if err := write(out, format, records, export.ParseColumns(opts.columns), enrichTableColumns); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d companies, %d enriched, %d failed\n", len(records), len(records)-failed, failed)
+	if runErr != nil {
+		return runErr
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+func runServe(_ context.Context, args []string) error {
+	flags := newFlagSet("serve", "")
+	port := flags.String("port", firstNonEmpty(os.Getenv("PORT"), "3000"), "port to listen on")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(flags, args); err != nil {
+		return err
+	}
+	return server.Run(*port)
+}
+
+// enrichAll enriches the companies one after the other, at most one every
+// services.DefaultCSVRowInterval like the CSV enrichment, reporting failures on stderr
+// and in their record. It returns the records with the number of failures, and stops
+// with the error of ctx when it is cancelled.
+func enrichAll(ctx context.Context, slugs []string, opts options, enrichOpts services.EnrichOptions) ([]enrichRecord, int, error) {
+	e := opts.enricher()
+	pacer := services.NewPacer(services.DefaultCSVRowInterval)
+	records := make([]enrichRecord, 0, len(slugs))
+	failed := 0
+	for _, slug := range slugs {
+		if err := pacer.Wait(ctx); err != nil {
+			return records, failed, err
+		}
+		result, err := e.EnrichCompany(ctx, slug, enrichOpts)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return records, failed, ctxErr
+		}
+		record := enrichRecord{Slug: slug, CompanyResult: result}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", slug, err)
+			record.Error = err.Error()
+			failed++
+		}
+		records = append(records, record)
+	}
+	return records, failed, nil
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readSlugs reads one company per line, skipping blank lines and '#' comments. LinkedIn
+// company URLs are accepted in place of slugs; other URLs are refused.
+func readSlugs(r io.Reader) ([]string, error) {
+	var slugs []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.Contains(text, "/") {
+			slug := utils.CompanySlugFromURL(utils.NormalizeURL(text))
+			if slug == "" {
+				return nil, fmt.Errorf("line %d: not a LinkedIn company URL: %s", line, text)
+			}
+			text = slug
+		}
+		slugs = append(slugs, text)
+	}
+	return slugs, scanner.Err()
+}
+
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return formatCSV
+	case ".ndjson", ".jsonl":
+		return formatNDJSON
+	case ".txt":
+		return formatTable
+	}
+	return ""
+}
+
+// parseRecord is the output of one parsed file.
+type parseRecord struct {
+	File  string `json:"file"`
+	Error string `json:"error,omitempty"`
+	*services.CompanyResult
+	Diagnostics *parser.Diagnostics `json:"diagnostics,omitempty"`
+}
+
+func runParse(ctx context.Context, args []string) error {
+	var opts options
+	flags := newFlagSet("parse", "<file...>")
+	opts.register(flags)
+	slug := flags.String("slug", "", "company slug or ID the pages describe (default: read from the canonical URL)")
+	view := flags.String("view", services.ViewSummary, "payload view: summary, detailed or raw")
+	fields := flags.String("fields", "", "comma separated fields to return, nested with dots")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := opts.resolve(); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	if !services.IsValidView(*view) {
+		return fmt.Errorf("view must be one of summary, detailed, raw")
+	}
+
+	parseOpts := services.ParseOptions{
+		EnrichOptions: services.EnrichOptions{View: *view, Fields: utils.ParseFieldSet(*fields)},
+		Slug:          *slug,
+	}
+	e := opts.enricher()
+	records := make([]parseRecord, 0, len(args))
+	failed := 0
+	for _, path := range args {
+		record := parseRecord{File: path}
+		html, err := readFile(path)
+		if err == nil {
+			record.CompanyResult, err = e.ParseHTML(ctx, html, parseOpts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			record.Error = err.Error()
+			var diagErr *services.DiagnosticsError
+			if errors.As(err, &diagErr) {
+				record.Diagnostics = diagErr.Diagnostics
+			}
+			failed++
+		} else {
+			record.Diagnostics = record.CompanyResult.Diagnostics
+		}
+		records = append(records, record)
+	}
+
+	format := firstNonEmpty(opts.format, formatJSON)
+	var data interface{} = records
+	if len(records) == 1 && format == formatJSON {
+		data = records[0]
+	}
+	if err := write(os.Stdout, format, data, export.ParseColumns(opts.columns), parseTableColumns); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errFailed
+	}
+	return nil
+}
+
+var parseTableColumns = []string{"file", "scrapeType", "parser.strategy", "data.name", "data.website", "error"}
+
+// readFile reads a file, or stdin for "-".
+func readFile(path string) (string, error) {
+	in, err := openInput(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	raw, err := io.ReadAll(in)
+	return string(raw), err
+}
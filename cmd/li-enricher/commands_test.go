@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantArgs   []string
+		wantFormat string
+		wantDebug  bool
+	}{
+		{nil, nil, "", false},
+		{[]string{"acme"}, []string{"acme"}, "", false},
+		{[]string{"--format", "csv", "acme", "globex"}, []string{"acme", "globex"}, "csv", false},
+		{[]string{"acme", "--format=table", "globex", "-debug"}, []string{"acme", "globex"}, "table", true},
+		{[]string{"acme", "globex", "--debug"}, []string{"acme", "globex"}, "", true},
+		{[]string{"--debug", "--", "-acme"}, []string{"-acme"}, "", true},
+	}
+	for _, tt := range tests {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		format := flags.String("format", "", "")
+		debug := flags.Bool("debug", false, "")
+
+		args, err := parseArgs(flags, tt.args)
+		if err != nil {
+			t.Errorf("parseArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("parseArgs(%q) = %q, want %q", tt.args, args, tt.wantArgs)
+		}
+		if *format != tt.wantFormat || *debug != tt.wantDebug {
+			t.Errorf("parseArgs(%q): format %q, debug %v, want %q, %v", tt.args, *format, *debug, tt.wantFormat, tt.wantDebug)
+		}
+	}
+}
+
+func TestParseArgsUnknownFlag(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if _, err := parseArgs(flags, []string{"acme", "--nope"}); err == nil {
+		t.Error("parseArgs accepted an unknown flag after a positional argument")
+	}
+}
+
+func TestNoArgs(t *testing.T) {
+	flags := newFlagSet("serve", "")
+	flags.SetOutput(io.Discard)
+	if err := noArgs(flags, nil); err != nil {
+		t.Errorf("noArgs(nil) = %v, want nil", err)
+	}
+	if err := noArgs(flags, []string{"8080"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("noArgs(8080) = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestReadSlugs(t *testing.T) {
+	input := strings.Join([]string{
+		"# companies to enrich",
+		"acme",
+		"",
+		"   ",
+		"  globex  ",
+		"https://www.linkedin.com/company/initech/about/",
+		"www.linkedin.com/showcase/initech-labs",
+		"https://www.linkedin.com/company/hooli?utm_source=share#about",
+		"1234",
+	}, "\n")
+
+	slugs, err := readSlugs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"acme", "globex", "initech", "initech-labs", "hooli", "1234"}
+	if !reflect.DeepEqual(slugs, want) {
+		t.Errorf("readSlugs = %q, want %q", slugs, want)
+	}
+
+	for _, line := range []string{"https://evil.example/company/foo", "https://www.linkedin.com/in/someone", "a/b"} {
+		if slugs, err := readSlugs(strings.NewReader("acme\n" + line)); err == nil {
+			t.Errorf("readSlugs accepted %q as %q", line, slugs)
+		}
+	}
+}
+
+func TestFormatFromExtension(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"-":                "",
+		"out.csv":          formatCSV,
+		"OUT.CSV":          formatCSV,
+		"out.ndjson":       formatNDJSON,
+		"dir/out.jsonl":    formatNDJSON,
+		"out.txt":          formatTable,
+		"out.json":         "",
+		"archive.csv.gz":   "",
+		"dir.csv/out":      "",
+		"companies.tar.gz": "",
+	}
+	for path, want := range tests {
+		if got := formatFromExtension(path); got != want {
+			t.Errorf("formatFromExtension(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
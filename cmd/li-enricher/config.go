@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/vit0-9/li-enricher-api/enricher"
+)
+
+// fileConfig is the JSON config file.
+type fileConfig struct {
+	SessionCookie string `json:"session_cookie"`
+	ProxyURL      string `json:"proxy_url"`
+	Format        string `json:"format"`
+}
+
+// options holds the flags shared by the commands.
+type options struct {
+	cookie     string
+	proxy      string
+	configPath string
+	format     string
+	columns    string
+}
+
+func (o *options) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.cookie, "cookie", "", "LinkedIn 'li_at' session cookie")
+	flags.StringVar(&o.proxy, "proxy", "", "proxy URL for the LinkedIn requests")
+	flags.StringVar(&o.configPath, "config", "", "path of the JSON config file")
+	flags.StringVar(&o.format, "format", "", "output format: json (default), table, csv or ndjson")
+	flags.StringVar(&o.columns, "columns", "", "comma separated columns of the table and CSV output")
+}
+
+// resolve fills the options not given as flags from the environment, then from the
+// config file, and checks the output format. An empty format is left to the command.
+func (o *options) resolve() error {
+	cfg, err := loadConfig(o.configPath)
+	if err != nil {
+		return err
+	}
+	o.cookie = firstNonEmpty(o.cookie, os.Getenv("LI_SESSION_COOKIE"), cfg.SessionCookie)
+	o.proxy = firstNonEmpty(o.proxy, os.Getenv("LI_PROXY_URL"), cfg.ProxyURL)
+	o.format = firstNonEmpty(o.format, os.Getenv("LI_ENRICHER_FORMAT"), cfg.Format)
+	if o.format != "" && !isValidFormat(o.format) {
+		return fmt.Errorf("format must be one of json, table, csv, ndjson")
+	}
+	return nil
+}
+
+// loadConfig reads the config file at path, LI_ENRICHER_CONFIG or the default location.
+// A missing file is only an error when its path was given explicitly.
+func loadConfig(path string) (*fileConfig, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv("LI_ENRICHER_CONFIG")
+	}
+	if path == "" {
+		explicit = false
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return &fileConfig{}, nil
+		}
+		path = filepath.Join(dir, "li-enricher", "config.json")
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return &fileConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var cfg fileConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// enricher creates an enricher using the resolved cookie and proxy.
+func (o *options) enricher() *enricher.Enricher {
+	return enricher.New(
+		enricher.WithCookieProvider(enricher.StaticCookie(o.cookie)),
+		enricher.WithProxyProvider(enricher.StaticProxy(o.proxy)),
+	)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
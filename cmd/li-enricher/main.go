@@ -0,0 +1,71 @@
+// Command li-enricher enriches, searches and validates LinkedIn companies from the
+// command line, or serves the HTTP API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: li-enricher <command> [flags] [arguments]
+
+Commands:
+  enrich <slug...>                       enrich companies
+  search <query>                         search companies (requires a session cookie)
+  validate-cookie                        check that the session cookie is valid
+  batch --input file [--output file]     enrich the companies listed in a file
+  parse <file...>                        extract a company from saved HTML pages ('-' for stdin)
+  serve                                  serve the HTTP API
+
+The session cookie and proxy are read from the --cookie and --proxy flags, then from
+LI_SESSION_COOKIE and LI_PROXY_URL, then from the config file (--config, LI_ENRICHER_CONFIG
+or li-enricher/config.json in the user config directory).
+
+Run 'li-enricher <command> -h' for the flags of a command.
+`
+
+// errFailed reports that a command already printed its failures.
+var errFailed = errors.New("some companies could not be enriched")
+
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"enrich":          runEnrich,
+	"search":          runSearch,
+	"validate-cookie": runValidateCookie,
+	"batch":           runBatch,
+	"parse":           runParse,
+	"serve":           runServe,
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	// Interrupting cancels the LinkedIn requests in flight.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/vit0-9/li-enricher-api/export"
+)
+
+// Output formats.
+const (
+	formatJSON   = "json"
+	formatTable  = "table"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+func isValidFormat(format string) bool {
+	switch format {
+	case formatJSON, formatTable, formatCSV, formatNDJSON:
+		return true
+	}
+	return false
+}
+
+// write prints data in the given format. defaultColumns are the table columns used when
+// none were selected; CSV includes every column by default.
+func write(w io.Writer, format string, data interface{}, columns, defaultColumns []string) error {
+	switch format {
+	case formatTable:
+		if len(columns) == 0 {
+			columns = defaultColumns
+		}
+		return export.WriteTable(w, data, export.Options{Columns: columns})
+	case formatCSV:
+		return export.WriteCSV(w, data, export.Options{Columns: columns})
+	case formatNDJSON:
+		return export.WriteNDJSON(w, data)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
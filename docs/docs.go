@@ -19,14 +19,179 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/breakers": {
+            "get": {
+                "description": "Lists the circuit breakers guarding the LinkedIn requests: one per target ('target:company_pages', 'target:voyager_api', 'target:feed_validation') and one per session cookie ('session:' followed by a hash of the cookie), created on first use.\nA breaker opens when the failed share of its requests (connection errors, 429, 5xx and 999 responses, redirects to the login page, which only count against the session cookie of authenticated requests) reaches the configured ratio. While open, the requests it guards fail fast with 503. Once 'retry_at' passes, it lets probe requests through and closes when they succeed.\nThe admin endpoints are disabled unless the server sets ADMIN_TOKEN, and then require it as a bearer token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Circuit Breakers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer followed by the ADMIN_TOKEN of the server",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "breakers": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/scraper.BreakerState"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or wrong admin token",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "The admin endpoints are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/companies/enrich-csv": {
+            "post": {
+                "description": "Enriches the companies of an uploaded CSV and returns it with the columns li_status ('ok' or 'failed'), li_error, li_slug and the enrichment columns (prefixed with 'li_') appended, in the original row order. 'column' is the header or 0-based index of the column holding the identifiers and 'identifier_type' says what they are. Resolving names and domains searches LinkedIn and requires a session cookie; a domain matches the first of the top search results whose website is on it.\nThe X-Rows-Enriched and X-Rows-Failed response headers count the rows. At most 200 rows are enriched, with at most one LinkedIn lookup per second (a name takes two lookups, a domain up to four); when the request deadline (X-Request-Timeout, or the server default) passes first, the request fails with 504.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Enrich CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV with a header row",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Header or 0-based index of the identifier column",
+                        "name": "column",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "slug",
+                            "url",
+                            "name",
+                            "domain"
+                        ],
+                        "type": "string",
+                        "description": "What the column holds",
+                        "name": "identifier_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated enrichment columns (e.g. 'name,website,headquarters.country'); all fields by default",
+                        "name": "columns",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for authenticated scraping",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL to use for scraping",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The enriched CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The request deadline passed before every row was enriched",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/companies/search/{query}": {
             "get": {
-                "description": "Searches for companies using LinkedIn GraphQL API with the given query string and session cookie.",
+                "description": "Searches for companies using LinkedIn GraphQL API with the given query string and session cookie.\nResults are returned as CSV or NDJSON with 'Accept: text/csv' or 'Accept: application/x-ndjson'.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "LinkedIn"
@@ -40,23 +205,45 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return (e.g. 'id,name')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV only: columns in order, nested with dots",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "CSV only: levels of nested objects flattened into columns, 0 for all",
+                        "name": "max_depth",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "LinkedIn session cookie (li_at)",
                         "name": "X-Linkedin-Session-Cookie",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "valid": {
-                                    "type": "boolean"
-                                }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.SearchResult"
                             }
                         }
                     },
@@ -84,18 +271,48 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    "503": {
+                        "description": "A circuit breaker refused the LinkedIn request, see Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The deadline passed before LinkedIn answered",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
                     }
                 }
             }
         },
         "/companies/{slug}": {
             "get": {
-                "description": "Scrapes data for a LinkedIn company page. If a session cookie is provided via the 'X-Linkedin-Session-Cookie' header, it performs a full, authenticated scrape. Otherwise, it performs a public scrape for basic JSON-LD data.",
+                "description": "Scrapes data for a LinkedIn company page. If a session cookie is provided via the 'X-Linkedin-Session-Cookie' header, it performs a full, authenticated scrape. Otherwise, it performs a public scrape of the guest page's about section and JSON-LD data.\n'view' selects the payload: 'summary' (default), 'detailed' for the resolved company entity or 'raw' for the selected bpr-guid JSON (a list when several blocks describe the company), the guest page sections or the ld+json untouched. 'debug=true' adds diagnostics about the fetched page.\n'as_of' returns the stored snapshot valid at that date instead of scraping LinkedIn.\nWith 'Accept: text/csv' or 'Accept: application/x-ndjson' the company payload ('data') is exported as one row, nested fields flattened into dotted columns.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "Company"
@@ -109,6 +326,47 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "summary",
+                            "detailed",
+                            "raw"
+                        ],
+                        "type": "string",
+                        "description": "Payload view",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include diagnostics",
+                        "name": "debug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, nested with dots (e.g. 'name,website,headquarters.country')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (2026-07-01) or RFC 3339 time of the snapshot to return",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV only: columns in order, nested with dots (e.g. 'name,headquarters.city,funding_summary.last_round.type')",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "CSV only: levels of nested objects flattened into columns, 0 for all",
+                        "name": "max_depth",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "LinkedIn 'li_at' session cookie for authenticated scraping",
@@ -120,19 +378,56 @@ const docTemplate = `{
                         "description": "Proxy URL to use for validation",
                         "name": "X-Proxy-Url",
                         "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully scraped data. 'scrapeType' will be 'full' or 'public'.",
+                        "description": "Successfully scraped data. 'scrapeType' will be 'full' or 'public', 'parser' names the extraction strategy and version used, 'attempts' counts the LinkedIn requests, retries included, 'snapshot' identifies the snapshot the result was recorded as or read from.",
                         "schema": {
                             "type": "object",
                             "properties": {
+                                "attempts": {
+                                    "type": "integer"
+                                },
                                 "data": {
                                     "type": "object"
                                 },
+                                "diagnostics": {
+                                    "$ref": "#/definitions/parser.Diagnostics"
+                                },
+                                "parser": {
+                                    "type": "object",
+                                    "properties": {
+                                        "strategy": {
+                                            "type": "string"
+                                        },
+                                        "version": {
+                                            "type": "string"
+                                        }
+                                    }
+                                },
                                 "scrapeType": {
                                     "type": "string"
+                                },
+                                "snapshot": {
+                                    "type": "object",
+                                    "properties": {
+                                        "captured_at": {
+                                            "type": "string"
+                                        },
+                                        "id": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                },
+                                "view": {
+                                    "type": "string"
                                 }
                             }
                         }
@@ -148,8 +443,50 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "No snapshot at the 'as_of' date",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "diagnostics": {
+                                    "$ref": "#/definitions/parser.Diagnostics"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "A circuit breaker refused the LinkedIn request, see Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The deadline passed before LinkedIn answered",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -165,41 +502,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/validate-cookie": {
+        "/companies/{slug}/changes": {
             "get": {
-                "description": "Checks if a given LinkedIn session cookie ('li_at') is valid and active.",
+                "description": "Compares the latest snapshot of a company with a previous one field by field. Lists such as office_locations and specialities are compared by element. By default the previous snapshot of the same scrape type is used; 'from' selects a snapshot by ID and 'since' the snapshot valid at a date, or the one before the latest snapshot when that is the latest. 'refresh=true' scrapes the company first, so the comparison starts from a fresh result.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Company"
                 ],
-                "summary": "Validate Session Cookie",
+                "summary": "Company Changes",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "LinkedIn 'li_at' session cookie",
-                        "name": "X-Linkedin-Session-Cookie",
-                        "in": "header",
+                        "description": "Company Slug (e.g., 'google')",
+                        "name": "slug",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "ID of the snapshot to compare with",
+                        "name": "from",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Proxy URL to use for validation",
+                        "description": "Date (2026-07-01) or RFC 3339 time of the snapshot to compare with",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Scrape the company before comparing",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie, used with refresh",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL, used with refresh",
                         "name": "X-Proxy-Url",
                         "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "valid": {
-                                    "type": "boolean"
-                                }
-                            }
+                            "$ref": "#/definitions/services.ChangeSet"
                         }
                     },
                     "400": {
@@ -213,14 +575,1117 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "properties": {
-                                "details": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "A circuit breaker refused the LinkedIn request, see Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The deadline passed before LinkedIn answered",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/companies/{slug}/history": {
+            "get": {
+                "description": "Lists the snapshots stored for a company, newest first, without their data. Every successful enrichment in the summary view is stored as a snapshot, under the company's universal name: a company enriched by numeric ID or by slug has one history, which either identifier lists. The list is paginated: 'next_offset' is set when more snapshots may follow.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Company History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Company Slug (e.g., 'google')",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of snapshots to return (default 100, at most 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of snapshots to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "next_offset": {
+                                    "type": "integer"
+                                },
+                                "slug": {
+                                    "type": "string"
+                                },
+                                "snapshots": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/history.Snapshot"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/companies/{slug}/similar": {
+            "get": {
+                "description": "Returns the \"Similar pages\" / \"People also viewed\" companies of a LinkedIn company page, for lookalike prospecting. Works with and without a session cookie.\nWith 'Accept: text/csv' or 'Accept: application/x-ndjson' the similar companies are exported one per row.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Similar Companies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Company Slug (e.g., 'google')",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields of every similar company to return (e.g. 'name,url')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV only: columns in order",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for authenticated scraping",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL to use for the request",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "similar_companies": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/parser.SimilarCompany"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "A circuit breaker refused the LinkedIn request, see Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The deadline passed before LinkedIn answered",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "get": {
+                "description": "Executes a GraphQL query over companies ('company', 'companies'), their locations, funding and similar companies, and company searches ('searchCompanies'). Every company is fetched once per query, and the companies requested at the same depth, e.g. the similar companies of a company, are enriched together.\nThe query is sent as a JSON body, or as the 'query' and 'variables' query parameters of a GET request. Field errors are reported in 'errors' next to the data of the fields that succeeded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "GraphQL Query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/graphqlapi.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "GraphQL query (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object of variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for authenticated scraping",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL to use for the LinkedIn requests",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "object"
+                                },
+                                "errors": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "message": {
+                                                "type": "string"
+                                            },
+                                            "path": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Executes a GraphQL query over companies ('company', 'companies'), their locations, funding and similar companies, and company searches ('searchCompanies'). Every company is fetched once per query, and the companies requested at the same depth, e.g. the similar companies of a company, are enriched together.\nThe query is sent as a JSON body, or as the 'query' and 'variables' query parameters of a GET request. Field errors are reported in 'errors' next to the data of the fields that succeeded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "GraphQL Query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/graphqlapi.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "GraphQL query (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object of variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for authenticated scraping",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL to use for the LinkedIn requests",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "object"
+                                },
+                                "errors": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "message": {
+                                                "type": "string"
+                                            },
+                                            "path": {
+                                                "type": "array",
+                                                "items": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/parse": {
+            "post": {
+                "description": "Runs a saved LinkedIn company page through the same extraction as the live endpoint, without fetching anything, and returns the company payload with diagnostics about which extraction paths matched. The HTML is sent as the request body, or as the 'file' field of a multipart form.\n'slug' names the company the page describes; by default it is read from the page's canonical URL.\nWith 'Accept: text/csv' or 'Accept: application/x-ndjson' the company payload ('data') is exported as one row.",
+                "consumes": [
+                    "text/html",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Company"
+                ],
+                "summary": "Parse Saved Page",
+                "parameters": [
+                    {
+                        "description": "Saved HTML page",
+                        "name": "html",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Saved HTML page",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Company slug or ID the page describes",
+                        "name": "slug",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "summary",
+                            "detailed",
+                            "raw"
+                        ],
+                        "type": "string",
+                        "description": "Payload view",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to return, nested with dots (e.g. 'name,website,headquarters.country')",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CSV only: columns in order, nested with dots",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "CSV only: levels of nested objects flattened into columns, 0 for all",
+                        "name": "max_depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "object"
+                                },
+                                "diagnostics": {
+                                    "$ref": "#/definitions/parser.Diagnostics"
+                                },
+                                "parser": {
+                                    "type": "object",
+                                    "properties": {
+                                        "strategy": {
+                                            "type": "string"
+                                        },
+                                        "version": {
+                                            "type": "string"
+                                        }
+                                    }
+                                },
+                                "scrapeType": {
+                                    "type": "string"
+                                },
+                                "view": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No company data found in the page",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "diagnostics": {
+                                    "$ref": "#/definitions/parser.Diagnostics"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/validate-cookie": {
+            "get": {
+                "description": "Checks if a given LinkedIn session cookie ('li_at') is valid and active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Validate Session Cookie",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL to use for validation",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted",
+                        "name": "X-Request-Timeout",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "valid": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "A circuit breaker refused the LinkedIn request, see Retry-After",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "The deadline passed before LinkedIn answered",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlists": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "List Watchlists",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "watchlists": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/watch.Watchlist"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers a list of company slugs re-enriched on a cron schedule (5-field cron expression or descriptors such as '@daily' and '@every 6h'). The session cookie and proxy headers are stored with the watchlist and used for the scheduled scrapes; the cookie is stored encrypted and refused when the server has no cookie key. When a company's fields change, a change event is sent to the configured sinks and to the watchlist's webhook, which must be an http(s) URL of a public address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "Create Watchlist",
+                "parameters": [
+                    {
+                        "description": "Watchlist",
+                        "name": "watchlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.watchlistRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for the scheduled scrapes",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL for the scheduled scrapes",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/watch.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlists/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "Get Watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/watch.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the name, slugs, schedule and webhook of a watchlist. The stored session cookie and proxy are replaced when the headers are sent, and kept otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "Update Watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Watchlist",
+                        "name": "watchlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.watchlistRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "LinkedIn 'li_at' session cookie for the scheduled scrapes",
+                        "name": "X-Linkedin-Session-Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proxy URL for the scheduled scrapes",
+                        "name": "X-Proxy-Url",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/watch.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "details": {
+                                    "type": "string"
+                                },
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "Delete Watchlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlists/{id}/schedule": {
+            "get": {
+                "description": "Returns, per company of the watchlist, the next scheduled run and the outcome of the last one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Watchlists"
+                ],
+                "summary": "Watchlist Schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "runs": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/watch.RunState"
+                                    }
+                                },
+                                "schedule": {
                                     "type": "string"
                                 },
+                                "watchlist_id": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "error": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Watchlists are disabled",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
                                 "error": {
                                     "type": "string"
                                 }
@@ -230,6 +1695,328 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "definitions": {
+        "graphqlapi.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "history.Change": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {},
+                "path": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/history.ChangeType"
+                }
+            }
+        },
+        "history.ChangeType": {
+            "type": "string",
+            "enum": [
+                "added",
+                "removed",
+                "modified"
+            ],
+            "x-enum-varnames": [
+                "ChangeAdded",
+                "ChangeRemoved",
+                "ChangeModified"
+            ]
+        },
+        "history.Snapshot": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "parser": {
+                    "$ref": "#/definitions/parser.StrategyInfo"
+                },
+                "scrapeType": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "parser.BlockDiagnostics": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "passed_validation": {
+                    "type": "boolean"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "top_level_keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid_json": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "parser.Diagnostics": {
+            "type": "object",
+            "properties": {
+                "bpr_guid_blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/parser.BlockDiagnostics"
+                    }
+                },
+                "bpr_guid_blocks_found": {
+                    "type": "integer"
+                },
+                "bpr_guid_blocks_valid": {
+                    "type": "integer"
+                },
+                "final_url": {
+                    "type": "string"
+                },
+                "html_size": {
+                    "type": "integer"
+                },
+                "ld_json_found": {
+                    "type": "boolean"
+                },
+                "strategies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/parser.StrategyDiagnostics"
+                    }
+                }
+            }
+        },
+        "parser.SimilarCompany": {
+            "type": "object",
+            "properties": {
+                "follower_count": {
+                    "type": "integer"
+                },
+                "industry": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "parser.StrategyDiagnostics": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "type": "boolean"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "parser.StrategyInfo": {
+            "type": "object",
+            "properties": {
+                "strategy": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.watchlistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "0 6 * * 1"
+                },
+                "slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "scraper.BreakerState": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer"
+                },
+                "last_failure": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "retry_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.ChangeSet": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/history.Change"
+                    }
+                },
+                "from": {
+                    "$ref": "#/definitions/services.SnapshotInfo"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "to": {
+                    "$ref": "#/definitions/services.SnapshotInfo"
+                }
+            }
+        },
+        "services.SearchResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.SnapshotInfo": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "scrapeType": {
+                    "type": "string"
+                }
+            }
+        },
+        "watch.RunState": {
+            "type": "object",
+            "properties": {
+                "last_changes": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "type": "string"
+                },
+                "last_status": {
+                    "type": "string"
+                },
+                "next_run": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "watch.Watchlist": {
+            "type": "object",
+            "properties": {
+                "authenticated": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "type": "string"
+                },
+                "slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                }
+            }
+        }
     }
 }`
 
@@ -0,0 +1,139 @@
+package enricher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// Cache stores enrichment results by key. Implementations must be safe for concurrent
+// use; a Redis or database backed cache can be plugged in with WithCache.
+type Cache interface {
+	Get(ctx context.Context, key string) (*CompanyResult, bool)
+	Set(ctx context.Context, key string, result *CompanyResult)
+}
+
+// MemoryCache is an in-process Cache whose entries expire after a TTL.
+type MemoryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	result    *CompanyResult
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates a cache keeping results for ttl. When it holds maxEntries
+// results, expired entries are dropped, then the oldest; zero means no limit.
+func NewMemoryCache(ttl time.Duration, maxEntries int) *MemoryCache {
+	return &MemoryCache{ttl: ttl, maxEntries: maxEntries, entries: map[string]cacheEntry{}}
+}
+
+func (c *MemoryCache) Get(_ context.Context, key string) (*CompanyResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, result *CompanyResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cacheEntry{result: result, expiresAt: now.Add(c.ttl)}
+}
+
+// evict drops the expired entries, or the entry expiring first when none has expired.
+func (c *MemoryCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// cacheKey identifies an enrichment: results scraped with different credentials are
+// never shared, so that a caller is not served what another one's session cookie or
+// proxy could see, and the views and fieldsets differ too. Slugs are matched regardless
+// of case and surrounding spaces, as LinkedIn does. The credentials are only kept as a
+// fingerprint.
+func cacheKey(slug string, creds credentials, opts services.EnrichOptions) string {
+	view := opts.View
+	if view == "" {
+		view = services.ViewSummary
+	}
+	fields, _ := json.Marshal(opts.Fields)
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	return fmt.Sprintf("%s|%s|%s|%s", slug, credentialsFingerprint(creds), view, fields)
+}
+
+// credentialsFingerprint identifies a session cookie and proxy without revealing them.
+// It is empty for public scrapes without a proxy.
+func credentialsFingerprint(creds credentials) string {
+	if creds.cookie == "" && creds.proxy == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(creds.cookie + "\x00" + creds.proxy))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cloneResult copies a result shared with a cache, down to its data, so that callers
+// cannot change the cached entry nor each other's results.
+func cloneResult(result *CompanyResult) *CompanyResult {
+	clone := *result
+	clone.Data = cloneData(result.Data)
+	if result.Snapshot != nil {
+		snapshot := *result.Snapshot
+		clone.Snapshot = &snapshot
+	}
+	return &clone
+}
+
+// cloneData deep-copies a JSON payload by encoding and decoding it into a value of the
+// same type, so typed payloads such as *parser.LiCompany keep their type.
+func cloneData(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	copied := reflect.New(reflect.TypeOf(data))
+	if err := json.Unmarshal(raw, copied.Interface()); err != nil {
+		return data
+	}
+	return copied.Elem().Interface()
+}
@@ -0,0 +1,75 @@
+package enricher
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+func TestCacheKeySeparatesCredentials(t *testing.T) {
+	opts := services.EnrichOptions{}
+	keys := map[string]string{
+		"public":       cacheKey("google", credentials{}, opts),
+		"cookie a":     cacheKey("google", credentials{cookie: "a"}, opts),
+		"cookie b":     cacheKey("google", credentials{cookie: "b"}, opts),
+		"cookie proxy": cacheKey("google", credentials{cookie: "a", proxy: "http://proxy:8080"}, opts),
+		"proxy":        cacheKey("google", credentials{proxy: "http://proxy:8080"}, opts),
+	}
+	seen := map[string]string{}
+	for name, key := range keys {
+		if other, ok := seen[key]; ok {
+			t.Errorf("%s and %s share the cache key %q", name, other, key)
+		}
+		seen[key] = name
+		if strings.Contains(key, "proxy:8080") {
+			t.Errorf("%s: key %q reveals the credentials", name, key)
+		}
+	}
+	if cacheKey("google", credentials{cookie: "a"}, opts) != keys["cookie a"] {
+		t.Error("the same credentials get different keys")
+	}
+}
+
+func TestCacheKeyIgnoresSlugCaseAndSpaces(t *testing.T) {
+	opts := services.EnrichOptions{}
+	creds := credentials{cookie: "a"}
+	want := cacheKey("google", creds, opts)
+	for _, slug := range []string{"Google", "GOOGLE", " google ", "\tGoogle\n"} {
+		if got := cacheKey(slug, creds, opts); got != want {
+			t.Errorf("cacheKey(%q) = %q, want the key of google %q", slug, got, want)
+		}
+	}
+	if cacheKey("google-cloud", creds, opts) == want {
+		t.Error("google-cloud shares the cache key of google")
+	}
+
+	cache := NewMemoryCache(time.Minute, 0)
+	cache.Set(context.Background(), cacheKey("Google", creds, opts), &CompanyResult{View: services.ViewSummary})
+	if _, ok := cache.Get(context.Background(), cacheKey("google", creds, opts)); !ok {
+		t.Error("a result cached for Google missed for google")
+	}
+}
+
+func TestCloneResultCopiesData(t *testing.T) {
+	summary := &CompanyResult{Data: map[string]interface{}{"name": "Acme", "specialities": []interface{}{"Search"}}}
+	clone := cloneResult(summary)
+	clone.Data.(map[string]interface{})["name"] = "Changed"
+	clone.Data.(map[string]interface{})["specialities"].([]interface{})[0] = "Changed"
+	if data := summary.Data.(map[string]interface{}); data["name"] != "Acme" || data["specialities"].([]interface{})[0] != "Search" {
+		t.Errorf("the original data changed: %v", data)
+	}
+
+	public := &CompanyResult{Data: &parser.LiCompany{Name: "Acme", Specialities: []string{"Search"}}}
+	company, ok := cloneResult(public).Data.(*parser.LiCompany)
+	if !ok {
+		t.Fatalf("cloned data is %T, want *parser.LiCompany", cloneResult(public).Data)
+	}
+	company.Specialities[0] = "Changed"
+	if public.Data.(*parser.LiCompany).Specialities[0] != "Search" {
+		t.Error("the original public data changed")
+	}
+}
@@ -0,0 +1,75 @@
+package enricher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoSessionCookie is returned by the methods requiring a session cookie when none
+// is available.
+var ErrNoSessionCookie = errors.New("a LinkedIn session cookie is required")
+
+// CookieProvider supplies the LinkedIn 'li_at' session cookie of a call, e.g. from a
+// pool of accounts. An empty cookie means a public scrape.
+type CookieProvider interface {
+	SessionCookie(ctx context.Context) (string, error)
+}
+
+// CookieProviderFunc adapts a function to a CookieProvider.
+type CookieProviderFunc func(ctx context.Context) (string, error)
+
+func (f CookieProviderFunc) SessionCookie(ctx context.Context) (string, error) { return f(ctx) }
+
+// StaticCookie always provides the same session cookie.
+func StaticCookie(cookie string) CookieProvider {
+	return CookieProviderFunc(func(context.Context) (string, error) { return cookie, nil })
+}
+
+// ProxyProvider supplies the proxy URL of a call. An empty URL means no proxy.
+type ProxyProvider interface {
+	ProxyURL(ctx context.Context) (string, error)
+}
+
+// ProxyProviderFunc adapts a function to a ProxyProvider.
+type ProxyProviderFunc func(ctx context.Context) (string, error)
+
+func (f ProxyProviderFunc) ProxyURL(ctx context.Context) (string, error) { return f(ctx) }
+
+// StaticProxy always provides the same proxy URL.
+func StaticProxy(proxyURL string) ProxyProvider {
+	return ProxyProviderFunc(func(context.Context) (string, error) { return proxyURL, nil })
+}
+
+type credentialsKey struct{}
+
+type credentials struct {
+	cookie string
+	proxy  string
+}
+
+// ContextWithCredentials returns a context whose calls use the given session cookie
+// and proxy instead of the providers'. Empty values fall back to the providers.
+func ContextWithCredentials(ctx context.Context, sessionCookie, proxyURL string) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, credentials{cookie: sessionCookie, proxy: proxyURL})
+}
+
+// credentials returns the session cookie and proxy of a call.
+func (e *Enricher) credentials(ctx context.Context) (credentials, error) {
+	creds, _ := ctx.Value(credentialsKey{}).(credentials)
+	if creds.cookie == "" && e.cookies != nil {
+		cookie, err := e.cookies.SessionCookie(ctx)
+		if err != nil {
+			return creds, fmt.Errorf("failed to get session cookie: %w", err)
+		}
+		creds.cookie = cookie
+	}
+	if creds.proxy == "" && e.proxies != nil {
+		proxyURL, err := e.proxies.ProxyURL(ctx)
+		if err != nil {
+			return creds, fmt.Errorf("failed to get proxy: %w", err)
+		}
+		creds.proxy = proxyURL
+	}
+	return creds, nil
+}
@@ -0,0 +1,276 @@
+// Package enricher enriches LinkedIn companies from Go programs, without the HTTP
+// server. It is the API the REST routes, the CLI and other workers are built on.
+//
+//	e := enricher.New(enricher.WithCookieProvider(enricher.StaticCookie(liAt)))
+//	result, err := e.EnrichCompany(ctx, "google", enricher.EnrichOptions{})
+package enricher
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/history"
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/scraper"
+	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// Results and options, shared with the services.
+type (
+	CompanyResult    = services.CompanyResult
+	Company          = services.Company
+	Location         = services.Location
+	Funding          = services.Funding
+	FundingRound     = services.FundingRound
+	Money            = services.Money
+	Investor         = services.Investor
+	EnrichOptions    = services.EnrichOptions
+	ParseOptions     = services.ParseOptions
+	CSVEnrichOptions = services.CSVEnrichOptions
+	CSVEnrichReport  = services.CSVEnrichReport
+	SearchResult     = services.SearchResult
+	ChangeSet        = services.ChangeSet
+	SimilarCompany   = parser.SimilarCompany
+	Snapshot         = history.Snapshot
+	RetryPolicy      = scraper.RetryPolicy
+	BreakerPolicy    = scraper.BreakerPolicy
+	BreakerState     = scraper.BreakerState
+)
+
+// ErrTimeout is returned when the deadline of the context passes before LinkedIn
+// answers. Such errors also match context.DeadlineExceeded.
+var ErrTimeout = scraper.ErrTimeout
+
+// ErrCircuitOpen is matched by the errors of the requests refused, without reaching
+// LinkedIn, because their circuit breaker is open.
+var ErrCircuitOpen = scraper.ErrCircuitOpen
+
+// Enricher scrapes and extracts LinkedIn companies. It is safe for concurrent use.
+type Enricher struct {
+	companies *services.CompanyService
+	auth      *services.AuthService
+	search    *services.SearchService
+	scraper   *scraper.Client
+	cookies   CookieProvider
+	proxies   ProxyProvider
+	cache     Cache
+	logger    *log.Logger
+}
+
+type config struct {
+	cookies    CookieProvider
+	proxies    ProxyProvider
+	httpClient *req.Client
+	cache      Cache
+	logger     *log.Logger
+	history    history.Store
+	retry      *RetryPolicy
+	breaker    *BreakerPolicy
+}
+
+// Option configures an Enricher.
+type Option func(*config)
+
+// WithCookieProvider sets where the LinkedIn session cookie comes from. Without one,
+// companies are scraped from their public page unless the context carries a cookie.
+func WithCookieProvider(provider CookieProvider) Option {
+	return func(c *config) { c.cookies = provider }
+}
+
+// WithProxyProvider sets the proxy the LinkedIn requests are sent through.
+func WithProxyProvider(provider ProxyProvider) Option {
+	return func(c *config) { c.proxies = provider }
+}
+
+// WithHTTPClient sets the client the LinkedIn requests are sent with. It is cloned
+// for every call. By default, a client impersonating Chrome is used.
+func WithHTTPClient(client *req.Client) Option {
+	return func(c *config) { c.httpClient = client }
+}
+
+// WithRetryPolicy sets how the LinkedIn requests are retried after connection errors
+// and 429, 5xx or 999 responses (default scraper.DefaultRetryPolicy()).
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *config) { c.retry = &policy }
+}
+
+// WithBreakerPolicy sets when the circuit breakers of the LinkedIn targets and
+// session cookies open (default scraper.DefaultBreakerPolicy()).
+func WithBreakerPolicy(policy BreakerPolicy) Option {
+	return func(c *config) { c.breaker = &policy }
+}
+
+// WithCache caches enrichments. Results read from the cache are not recorded as
+// snapshots; debug enrichments are never cached.
+func WithCache(cache Cache) Option {
+	return func(c *config) { c.cache = cache }
+}
+
+// WithLogger sets the logger of the enricher, the services and the scraper (default
+// log.Default()).
+// The parser package keeps logging to the standard logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *config) { c.logger = logger }
+}
+
+// WithHistory records every complete summary as a snapshot in store, which enables
+// History, CompanyAsOf and Changes.
+func WithHistory(store history.Store) Option {
+	return func(c *config) { c.history = store }
+}
+
+// New creates an Enricher.
+func New(opts ...Option) *Enricher {
+	cfg := &config{logger: log.Default()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	scraperOpts := []scraper.Option{scraper.WithLogger(cfg.logger)}
+	if cfg.retry != nil {
+		scraperOpts = append(scraperOpts, scraper.WithRetryPolicy(*cfg.retry))
+	}
+	if cfg.breaker != nil {
+		scraperOpts = append(scraperOpts, scraper.WithBreakerPolicy(*cfg.breaker))
+	}
+	client := scraper.NewClient(cfg.httpClient, scraperOpts...)
+	deps := services.Dependencies{
+		Scraper: client,
+		Logger:  cfg.logger,
+	}
+	return &Enricher{
+		companies: services.NewCompanyService(cfg.history, deps),
+		auth:      services.NewAuthService(deps),
+		search:    services.NewSearchService(deps),
+		scraper:   client,
+		cookies:   cfg.cookies,
+		proxies:   cfg.proxies,
+		cache:     cfg.cache,
+		logger:    cfg.logger,
+	}
+}
+
+// CompanyService returns the service the enricher is built on, for components such as
+// the watchlist scheduler that need its lower-level methods.
+func (e *Enricher) CompanyService() *services.CompanyService {
+	return e.companies
+}
+
+// Breakers returns the state of the circuit breakers guarding the LinkedIn requests.
+func (e *Enricher) Breakers() []BreakerState {
+	return e.scraper.Breakers()
+}
+
+// EnrichCompany scrapes and extracts a company. It is scraped with the session cookie
+// when there is one, from the public page otherwise.
+func (e *Enricher) EnrichCompany(ctx context.Context, slug string, opts EnrichOptions) (*CompanyResult, error) {
+	creds, err := e.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheable := e.cache != nil && !opts.Debug
+	key := cacheKey(slug, creds, opts)
+	if cacheable {
+		if result, ok := e.cache.Get(ctx, key); ok {
+			cached := cloneResult(result)
+			cached.Attempts = 0
+			return cached, nil
+		}
+	}
+
+	result, err := e.companies.EnrichCompanyData(ctx, slug, creds.cookie, creds.proxy, opts)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		e.cache.Set(ctx, key, cloneResult(result))
+	}
+	return result, nil
+}
+
+// SearchCompanies searches companies by name. It requires a session cookie.
+func (e *Enricher) SearchCompanies(ctx context.Context, query string) ([]SearchResult, error) {
+	creds, err := e.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if creds.cookie == "" {
+		return nil, ErrNoSessionCookie
+	}
+	return e.search.SearchCompanies(ctx, query, creds.cookie, creds.proxy)
+}
+
+// ValidateSession reports whether the session cookie is valid.
+func (e *Enricher) ValidateSession(ctx context.Context) (bool, error) {
+	creds, err := e.credentials(ctx)
+	if err != nil {
+		return false, err
+	}
+	if creds.cookie == "" {
+		return false, ErrNoSessionCookie
+	}
+	return e.auth.ValidateSession(ctx, creds.cookie, creds.proxy)
+}
+
+// SimilarCompanies returns the "Similar pages" of a company.
+func (e *Enricher) SimilarCompanies(ctx context.Context, slug string) ([]SimilarCompany, error) {
+	creds, err := e.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return e.companies.SimilarCompanies(ctx, slug, creds.cookie, creds.proxy)
+}
+
+// ParseHTML extracts a company from a saved page without fetching anything.
+func (e *Enricher) ParseHTML(ctx context.Context, html string, opts ParseOptions) (*CompanyResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.companies.ParseCompanyHTML(html, opts)
+}
+
+// EnrichCSV enriches every row of a CSV. The session cookie and proxy of opts default
+// to the enricher's.
+func (e *Enricher) EnrichCSV(ctx context.Context, in io.Reader, out io.Writer, opts CSVEnrichOptions) (*CSVEnrichReport, error) {
+	creds, err := e.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SessionCookie == "" {
+		opts.SessionCookie = creds.cookie
+	}
+	if opts.ProxyURL == "" {
+		opts.ProxyURL = creds.proxy
+	}
+	return e.companies.EnrichCSV(ctx, in, out, opts)
+}
+
+// History lists a page of the snapshots of a company, newest first, without their data.
+// A limit <= 0 returns the default page size.
+func (e *Enricher) History(ctx context.Context, slug string, limit, offset int) ([]Snapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.companies.History(slug, limit, offset)
+}
+
+// CompanyAsOf returns the company as it was recorded at the given time.
+func (e *Enricher) CompanyAsOf(ctx context.Context, slug string, at time.Time, fields utils.FieldSet) (*CompanyResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.companies.CompanyAsOf(slug, at, fields)
+}
+
+// Changes compares the latest snapshot of a company with a previous one.
+func (e *Enricher) Changes(ctx context.Context, slug string, fromID int64, since time.Time) (*ChangeSet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.companies.Changes(slug, fromID, since)
+}
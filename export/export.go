@@ -0,0 +1,286 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vit0-9/li-enricher-api/summarizer"
+)
+
+// Content types of the export formats.
+const (
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+)
+
+// Options controls how records are flattened into CSV columns.
+type Options struct {
+	// Columns selects and orders the columns, as dotted paths such as "headquarters.city".
+	// When empty, every flattened path of the records is exported: the fields of a
+	// result envelope first, then the summary fields in their schema order, then the
+	// other paths in alphabetical order.
+	Columns []string
+	// MaxDepth limits how many levels of nested objects are flattened into their own
+	// columns; deeper objects are written as JSON. Zero flattens everything.
+	MaxDepth int
+}
+
+// Records returns the records of a result: the elements of a list, or the value itself.
+// Values are converted to their JSON form, so structs are exported by their JSON names.
+func Records(data interface{}) ([]interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	if list, ok := generic.([]interface{}); ok {
+		return list, nil
+	}
+	if generic == nil {
+		return []interface{}{}, nil
+	}
+	return []interface{}{generic}, nil
+}
+
+// WriteNDJSON writes one JSON object per line.
+func WriteNDJSON(w io.Writer, data interface{}) error {
+	records, err := Records(data)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the records as CSV with a header row. Nested objects are flattened
+// into dotted columns, lists of scalars are joined with "; " and lists of objects are
+// written as JSON. Quoting of commas, quotes and newlines is handled by encoding/csv,
+// and every cell goes through SafeCell.
+func WriteCSV(w io.Writer, data interface{}, opts Options) error {
+	rows, columns, err := flattenRecords(data, opts)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = SafeCell(column)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	values := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			values[i] = SafeCell(row[column])
+		}
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTable writes the records as a text table with aligned columns, flattened like
+// WriteCSV. Tabs and newlines in cells are replaced by spaces.
+func WriteTable(w io.Writer, data interface{}, opts Options) error {
+	rows, columns, err := flattenRecords(data, opts)
+	if err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	values := make([]string, len(columns))
+	for _, row := range rows {
+		for i, column := range columns {
+			values[i] = tableCellReplacer.Replace(row[column])
+		}
+		fmt.Fprintln(table, strings.Join(values, "\t"))
+	}
+	return table.Flush()
+}
+
+var tableCellReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+// flattenRecords flattens the records of a result and returns them with the columns
+// selected by opts.
+func flattenRecords(data interface{}, opts Options) ([]map[string]string, []string, error) {
+	records, err := Records(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]map[string]string, 0, len(records))
+	for _, record := range records {
+		row := map[string]string{}
+		Flatten(record, opts.MaxDepth, row)
+		rows = append(rows, row)
+	}
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = allColumns(rows)
+	}
+	return rows, columns, nil
+}
+
+// Flatten writes the cells of a decoded JSON value into row, keyed by dotted path.
+// A scalar record is written under the "value" column.
+func Flatten(value interface{}, maxDepth int, row map[string]string) {
+	if _, ok := value.(map[string]interface{}); !ok {
+		row["value"] = Cell(value)
+		return
+	}
+	flatten("", value, 0, maxDepth, row)
+}
+
+func flatten(prefix string, value interface{}, depth, maxDepth int, row map[string]string) {
+	obj, ok := value.(map[string]interface{})
+	if !ok || (maxDepth > 0 && depth > maxDepth) {
+		row[prefix] = Cell(value)
+		return
+	}
+	for key, child := range obj {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flatten(path, child, depth+1, maxDepth, row)
+	}
+}
+
+// Cell formats a decoded JSON value as a CSV cell.
+func Cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		scalars := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				raw, _ := json.Marshal(v)
+				return string(raw)
+			}
+			scalars = append(scalars, Cell(item))
+		}
+		return strings.Join(scalars, "; ")
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
+
+// plainNumber matches the numbers and phone numbers that start like a formula, such as
+// "+33 1 23 45 67 89" or "-33.8688", which a spreadsheet reads as values.
+var plainNumber = regexp.MustCompile(`^[+-]?[0-9][0-9 .()-]*$`)
+
+// SafeCell neutralizes a cell that a spreadsheet would run as a formula, such as a
+// scraped description starting with "=HYPERLINK(", by prefixing it with a quote. Plain
+// numbers and phone numbers are left alone.
+func SafeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) && !plainNumber.MatchString(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// ParseColumns parses a comma separated list of columns.
+func ParseColumns(value string) []string {
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// envelopeFields are the fields of a result that describe the data rather than being
+// part of it, exported before it.
+var envelopeFields = []string{"slug", "scrapeType", "view", "parser", "error"}
+
+// columnRanks orders the columns by their first path segment, after an optional "data."
+// of a result envelope.
+var columnRanks = func() map[string]int {
+	ranks := map[string]int{}
+	for i, field := range envelopeFields {
+		ranks[field] = i
+	}
+	for i, field := range summarizer.Fields {
+		ranks["data."+field] = len(envelopeFields) + i
+		if _, ok := ranks[field]; !ok {
+			ranks[field] = len(envelopeFields) + i
+		}
+	}
+	return ranks
+}()
+
+func columnRank(column string) int {
+	head, rest, _ := strings.Cut(column, ".")
+	if head == "data" && rest != "" {
+		field, _, _ := strings.Cut(rest, ".")
+		head = "data." + field
+	}
+	if rank, ok := columnRanks[head]; ok {
+		return rank
+	}
+	return len(columnRanks)
+}
+
+func allColumns(rows []map[string]string) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, row := range rows {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	SortColumns(columns)
+	return columns
+}
+
+// SortColumns sorts flattened columns in the default export order: the fields of a
+// result envelope first, then the summary fields in their schema order, then the other
+// paths in alphabetical order.
+func SortColumns(columns []string) {
+	sort.Slice(columns, func(i, j int) bool {
+		if ri, rj := columnRank(columns[i]), columnRank(columns[j]); ri != rj {
+			return ri < rj
+		}
+		return columns[i] < columns[j]
+	})
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSVFlattensAndEscapes(t *testing.T) {
+	data := map[string]interface{}{
+		"name":        "Acme",
+		"description": "=HYPERLINK(\"http://evil\"), with commas\nand lines",
+		"headquarters": map[string]interface{}{
+			"city": "@Paris",
+		},
+		"funding_summary": map[string]interface{}{
+			"last_round": map[string]interface{}{"type": "Series A"},
+		},
+	}
+
+	var buf bytes.Buffer
+	opts := Options{Columns: []string{"name", "description", "headquarters.city", "funding_summary.last_round.type"}}
+	if err := WriteCSV(&buf, data, opts); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,description,headquarters.city,funding_summary.last_round.type\n" +
+		"Acme,\"'=HYPERLINK(\"\"http://evil\"\"), with commas\nand lines\",'@Paris,Series A\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSafeCell(t *testing.T) {
+	for value, want := range map[string]string{
+		"":                   "",
+		"Acme":               "Acme",
+		"=1+1":               "'=1+1",
+		"+33 1 23 45 67 89":  "+33 1 23 45 67 89",
+		"+1 (555) 010-0000":  "+1 (555) 010-0000",
+		"-2":                 "-2",
+		"-33.8688":           "-33.8688",
+		"+1+cmd|' /C calc'!": "'+1+cmd|' /C calc'!",
+		"-2+3":               "'-2+3",
+		"+":                  "'+",
+		"@SUM(A1)":           "'@SUM(A1)",
+		"\t=1":               "'\t=1",
+		"a=b, +c-d":          "a=b, +c-d",
+	} {
+		if got := SafeCell(value); got != want {
+			t.Errorf("SafeCell(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestWriteCSVDefaultColumnsFollowSummaryOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, map[string]interface{}{"website": "https://acme.com", "name": "Acme"}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(buf.String(), "\n"); header != "name,website" {
+		t.Errorf("header = %q, want name,website", header)
+	}
+}
@@ -0,0 +1,141 @@
+package graphqlapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// MaxCompaniesPerQuery caps the companies one query may enrich, counting the ones
+// reached through similarCompanies.
+const MaxCompaniesPerQuery = 25
+
+// loadConcurrency is the number of companies of a batch enriched at the same time.
+const loadConcurrency = 4
+
+// company is a company loaded for a query, with the summary read by the field resolvers.
+type company struct {
+	slug       string
+	scrapeType string
+	summary    *services.Company
+}
+
+type loadResult struct {
+	company *company
+	err     error
+}
+
+// companyLoader enriches every company of a query once. The resolvers register the
+// slugs they need and return thunks; the executor only runs the thunks once all the
+// fields at the same depth are resolved, so the first thunk enriches the whole batch.
+type companyLoader struct {
+	enricher *enricher.Enricher
+	ctx      context.Context
+
+	mu      sync.Mutex
+	pending []string
+	results map[string]*loadResult
+}
+
+func newCompanyLoader(ctx context.Context, e *enricher.Enricher) *companyLoader {
+	return &companyLoader{enricher: e, ctx: ctx, results: map[string]*loadResult{}}
+}
+
+// Load registers slug and returns a thunk resolving to its *company.
+func (l *companyLoader) Load(slug string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.results[slug]; !ok {
+		if len(l.results) >= MaxCompaniesPerQuery {
+			l.mu.Unlock()
+			return func() (interface{}, error) {
+				return nil, fmt.Errorf("a query can load at most %d companies", MaxCompaniesPerQuery)
+			}
+		}
+		l.results[slug] = nil
+		l.pending = append(l.pending, slug)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch()
+		l.mu.Lock()
+		result := l.results[slug]
+		l.mu.Unlock()
+		return result.company, result.err
+	}
+}
+
+// dispatch enriches the pending slugs.
+func (l *companyLoader) dispatch() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, loadConcurrency)
+	for _, slug := range batch {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(slug string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result := &loadResult{}
+			result.company, result.err = l.enrich(slug)
+			l.mu.Lock()
+			l.results[slug] = result
+			l.mu.Unlock()
+		}(slug)
+	}
+	wg.Wait()
+}
+
+func (l *companyLoader) enrich(slug string) (*company, error) {
+	result, err := l.enricher.EnrichCompany(l.ctx, slug, enricher.EnrichOptions{View: services.ViewSummary})
+	if err != nil {
+		return nil, err
+	}
+	summary := result.Company()
+	if summary == nil {
+		return nil, fmt.Errorf("no company summary for %s", slug)
+	}
+	return &company{slug: slug, scrapeType: result.ScrapeType, summary: summary}, nil
+}
+
+// searchLoader runs every distinct search of a query once.
+type searchLoader struct {
+	enricher *enricher.Enricher
+	ctx      context.Context
+
+	mu      sync.Mutex
+	results map[string]*searchResult
+}
+
+type searchResult struct {
+	once    sync.Once
+	results []enricher.SearchResult
+	err     error
+}
+
+func newSearchLoader(ctx context.Context, e *enricher.Enricher) *searchLoader {
+	return &searchLoader{enricher: e, ctx: ctx, results: map[string]*searchResult{}}
+}
+
+func (l *searchLoader) Load(query string) ([]enricher.SearchResult, error) {
+	l.mu.Lock()
+	result, ok := l.results[query]
+	if !ok {
+		result = &searchResult{}
+		l.results[query] = result
+	}
+	l.mu.Unlock()
+
+	result.once.Do(func() {
+		result.results, result.err = l.enricher.SearchCompanies(l.ctx, query)
+	})
+	return result.results, result.err
+}
@@ -0,0 +1,294 @@
+package graphqlapi
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// newSchema builds the schema. The companies are summaries: fields missing from a
+// public scrape resolve to null.
+func newSchema() (graphql.Schema, error) {
+	locationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Location",
+		Description: "An office of a company.",
+		Fields: graphql.Fields{
+			"isHeadquarters": &graphql.Field{Type: graphql.Boolean, Resolve: locationField(func(l *services.Location) interface{} { return l.IsHeadquarters })},
+			"line1":          &graphql.Field{Type: graphql.String, Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.Line1) })},
+			"line2":          &graphql.Field{Type: graphql.String, Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.Line2) })},
+			"city":           &graphql.Field{Type: graphql.String, Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.City) })},
+			"state":          &graphql.Field{Type: graphql.String, Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.State) })},
+			"country":        &graphql.Field{Type: graphql.String, Description: "ISO country code.", Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.Country) })},
+			"postalCode":     &graphql.Field{Type: graphql.String, Resolve: locationField(func(l *services.Location) interface{} { return nonZero(l.PostalCode) })},
+			"address": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The whole address on one line. Public scrapes only know this one.",
+				Resolve:     locationField(address),
+			},
+		},
+	})
+
+	moneyType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Money",
+		Fields: graphql.Fields{
+			"amount":   &graphql.Field{Type: graphql.Float, Resolve: sourceField(func(m *services.Money) interface{} { return m.Amount })},
+			"currency": &graphql.Field{Type: graphql.String, Resolve: sourceField(func(m *services.Money) interface{} { return nonZero(m.Currency) })},
+		},
+	})
+
+	investorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Investor",
+		Fields: graphql.Fields{
+			"name":          &graphql.Field{Type: graphql.String, Resolve: sourceField(func(i *services.Investor) interface{} { return nonZero(i.Name) })},
+			"crunchbaseUrl": &graphql.Field{Type: graphql.String, Resolve: sourceField(func(i *services.Investor) interface{} { return nonZero(i.CrunchbaseURL) })},
+		},
+	})
+
+	fundingRoundType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FundingRound",
+		Fields: graphql.Fields{
+			"type":          &graphql.Field{Type: graphql.String, Resolve: roundField(func(r *services.FundingRound) interface{} { return nonZero(r.Type) })},
+			"typeCode":      &graphql.Field{Type: graphql.String, Resolve: roundField(func(r *services.FundingRound) interface{} { return nonZero(r.TypeCode) })},
+			"announcedOn":   &graphql.Field{Type: graphql.String, Description: "Date of the announcement, 2006-01-02.", Resolve: roundField(func(r *services.FundingRound) interface{} { return nonZero(r.AnnouncedOn) })},
+			"moneyRaised":   &graphql.Field{Type: moneyType, Resolve: roundField(func(r *services.FundingRound) interface{} { return nonNil(r.MoneyRaised) })},
+			"leadInvestors": &graphql.Field{Type: graphql.NewList(investorType), Resolve: roundField(func(r *services.FundingRound) interface{} { return nonEmpty(r.LeadInvestors) })},
+			"investorCount": &graphql.Field{Type: graphql.Int, Resolve: roundField(func(r *services.FundingRound) interface{} { return nonZero(r.InvestorCount) })},
+			"crunchbaseUrl": &graphql.Field{Type: graphql.String, Resolve: roundField(func(r *services.FundingRound) interface{} { return nonZero(r.CrunchbaseURL) })},
+		},
+	})
+
+	fundingType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Funding",
+		Description: "Funding of a company, from Crunchbase. Only known from full scrapes.",
+		Fields: graphql.Fields{
+			"totalRounds":          &graphql.Field{Type: graphql.Int, Resolve: fundingField(func(f *services.Funding) interface{} { return nonZero(f.TotalRounds) })},
+			"investorCount":        &graphql.Field{Type: graphql.Int, Resolve: fundingField(func(f *services.Funding) interface{} { return nonZero(f.InvestorCount) })},
+			"lastRound":            &graphql.Field{Type: fundingRoundType, Resolve: fundingField(func(f *services.Funding) interface{} { return nonNil(f.LastRound) })},
+			"rounds":               &graphql.Field{Type: graphql.NewList(fundingRoundType), Resolve: fundingField(func(f *services.Funding) interface{} { return nonEmpty(f.Rounds) })},
+			"crunchbaseProfileUrl": &graphql.Field{Type: graphql.String, Resolve: fundingField(func(f *services.Funding) interface{} { return nonZero(f.CrunchbaseProfileURL) })},
+			"crunchbaseFundingUrl": &graphql.Field{Type: graphql.String, Resolve: fundingField(func(f *services.Funding) interface{} { return nonZero(f.CrunchbaseFundingURL) })},
+			"dataLastUpdated":      &graphql.Field{Type: graphql.String, Resolve: fundingField(func(f *services.Funding) interface{} { return nonZero(f.DataLastUpdated) })},
+		},
+	})
+
+	companyType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Company",
+		Fields: graphql.Fields{
+			"slug":          &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: companyField(func(c *company) interface{} { return c.slug })},
+			"scrapeType":    &graphql.Field{Type: graphql.String, Description: "\"full\" or \"public\".", Resolve: companyField(func(c *company) interface{} { return c.scrapeType })},
+			"name":          &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.Name) })},
+			"tagline":       &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.Tagline) })},
+			"description":   &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.Description) })},
+			"website":       &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.Website) })},
+			"linkedinUrl":   &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.LinkedinURL) })},
+			"industry":      &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.Industry) })},
+			"companyType":   &graphql.Field{Type: graphql.String, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.CompanyType) })},
+			"companySize":   &graphql.Field{Type: graphql.String, Description: "Employee count range, e.g. \"1001-5000\".", Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.CompanySize) })},
+			"foundedYear":   &graphql.Field{Type: graphql.Int, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.FoundedYear) })},
+			"followerCount": &graphql.Field{Type: graphql.Int, Resolve: summaryField(func(c *services.Company) interface{} { return nonZero(c.FollowerCount) })},
+			"specialities":  &graphql.Field{Type: graphql.NewList(graphql.String), Resolve: summaryField(func(c *services.Company) interface{} { return nonEmpty(c.Specialities) })},
+			"headquarters":  &graphql.Field{Type: locationType, Resolve: summaryField(func(c *services.Company) interface{} { return nonNil(c.Headquarters) })},
+			"locations":     &graphql.Field{Type: graphql.NewList(locationType), Resolve: summaryField(func(c *services.Company) interface{} { return nonEmpty(c.Locations) })},
+			"funding":       &graphql.Field{Type: fundingType, Resolve: summaryField(func(c *services.Company) interface{} { return nonNil(c.Funding) })},
+		},
+	})
+
+	similarCompanyType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "SimilarCompany",
+		Description: "A company of the \"Similar pages\" section.",
+		Fields: graphql.Fields{
+			"name":          &graphql.Field{Type: graphql.String, Resolve: similarField(func(s *parser.SimilarCompany) interface{} { return nonZero(s.Name) })},
+			"slug":          &graphql.Field{Type: graphql.String, Resolve: similarField(func(s *parser.SimilarCompany) interface{} { return nonZero(s.Slug) })},
+			"industry":      &graphql.Field{Type: graphql.String, Resolve: similarField(func(s *parser.SimilarCompany) interface{} { return nonZero(s.Industry) })},
+			"followerCount": &graphql.Field{Type: graphql.Int, Resolve: similarField(func(s *parser.SimilarCompany) interface{} { return nonZero(s.FollowerCount) })},
+			"logoUrl":       &graphql.Field{Type: graphql.String, Resolve: similarField(func(s *parser.SimilarCompany) interface{} { return nonZero(s.LogoURL) })},
+			"company": &graphql.Field{
+				Type:        companyType,
+				Description: "The similar company itself, enriched in the same batch as its siblings.",
+				Resolve:     resolveSimilarCompany,
+			},
+		},
+	})
+	// Company and SimilarCompany refer to each other.
+	companyType.AddFieldConfig("similarCompanies", &graphql.Field{
+		Type:    graphql.NewList(similarCompanyType),
+		Resolve: summaryField(func(c *services.Company) interface{} { return nonEmpty(c.SimilarCompanies) }),
+	})
+
+	searchResultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Description: "LinkedIn company ID."},
+			"name": &graphql.Field{Type: graphql.String},
+			"text": &graphql.Field{Type: graphql.String, Description: "Subtitle of the result, e.g. the industry and followers."},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"company": &graphql.Field{
+				Type: companyType,
+				Args: graphql.FieldConfigArgument{
+					"slug": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveCompany,
+			},
+			"companies": &graphql.Field{
+				Type:        graphql.NewList(companyType),
+				Description: "Several companies, in the order of the slugs.",
+				Args: graphql.FieldConfigArgument{
+					"slugs": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				},
+				Resolve: resolveCompanies,
+			},
+			"searchCompanies": &graphql.Field{
+				Type:        graphql.NewList(searchResultType),
+				Description: "Searches companies by name. Requires a session cookie.",
+				Args: graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolveSearchCompanies,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func resolveCompany(p graphql.ResolveParams) (interface{}, error) {
+	slug, _ := p.Args["slug"].(string)
+	if slug == "" {
+		return nil, errEmptySlug
+	}
+	return loaders(p).companies.Load(slug), nil
+}
+
+// resolveCompanies returns the companies as a list of thunks, so a failed company is
+// null in the list without failing the others.
+func resolveCompanies(p graphql.ResolveParams) (interface{}, error) {
+	slugs, _ := p.Args["slugs"].([]interface{})
+	if len(slugs) > MaxCompaniesPerQuery {
+		return nil, errTooManySlugs
+	}
+	companies := make([]interface{}, 0, len(slugs))
+	for _, slug := range slugs {
+		slug, _ := slug.(string)
+		if slug == "" {
+			return nil, errEmptySlug
+		}
+		companies = append(companies, loaders(p).companies.Load(slug))
+	}
+	return companies, nil
+}
+
+func resolveSimilarCompany(p graphql.ResolveParams) (interface{}, error) {
+	similar, ok := p.Source.(parser.SimilarCompany)
+	if !ok || similar.Slug == "" {
+		return nil, nil
+	}
+	return loaders(p).companies.Load(similar.Slug), nil
+}
+
+func resolveSearchCompanies(p graphql.ResolveParams) (interface{}, error) {
+	query, _ := p.Args["query"].(string)
+	if query == "" {
+		return nil, errEmptyQuery
+	}
+	results, err := loaders(p).searches.Load(query)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []enricher.SearchResult{}
+	}
+	// The default resolver reads struct fields by their JSON names.
+	return results, nil
+}
+
+// address returns the address of a location on one line. Public scrapes only know the
+// headquarters as one line.
+func address(l *services.Location) interface{} {
+	if l.Address != "" {
+		return l.Address
+	}
+	var parts []string
+	for _, part := range []string{l.Line1, l.Line2, l.City, l.State, l.PostalCode, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sourceField resolves a field of a T, the source of the object given as a value
+// (list elements) or a pointer.
+func sourceField[T any](get func(*T) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		switch source := p.Source.(type) {
+		case *T:
+			if source != nil {
+				return get(source), nil
+			}
+		case T:
+			return get(&source), nil
+		}
+		return nil, nil
+	}
+}
+
+func locationField(get func(*services.Location) interface{}) graphql.FieldResolveFn {
+	return sourceField(get)
+}
+
+func fundingField(get func(*services.Funding) interface{}) graphql.FieldResolveFn {
+	return sourceField(get)
+}
+
+func roundField(get func(*services.FundingRound) interface{}) graphql.FieldResolveFn {
+	return sourceField(get)
+}
+
+func similarField(get func(*parser.SimilarCompany) interface{}) graphql.FieldResolveFn {
+	return sourceField(get)
+}
+
+func companyField(get func(c *company) interface{}) graphql.FieldResolveFn {
+	return sourceField(get)
+}
+
+// summaryField resolves a field of the summary of a company.
+func summaryField(get func(c *services.Company) interface{}) graphql.FieldResolveFn {
+	return companyField(func(c *company) interface{} { return get(c.summary) })
+}
+
+// nonZero resolves the zero values, which the summaries use for unknown fields, to null.
+func nonZero[T comparable](value T) interface{} {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+// nonEmpty resolves an empty list to null, as a section the scrape does not know.
+func nonEmpty[T any](values []T) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// nonNil resolves a nil pointer to null rather than to a typed nil.
+func nonNil[T any](value *T) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value
+}
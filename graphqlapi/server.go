@@ -0,0 +1,69 @@
+// Package graphqlapi serves companies, their similar companies and company searches
+// through one GraphQL schema, so clients fetch everything they need in one round trip.
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vit0-9/li-enricher-api/enricher"
+)
+
+var (
+	errEmptySlug    = errors.New("company slug cannot be empty")
+	errEmptyQuery   = errors.New("search query cannot be empty")
+	errTooManySlugs = fmt.Errorf("at most %d slugs can be requested", MaxCompaniesPerQuery)
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Server executes GraphQL queries with an enricher.
+type Server struct {
+	schema   graphql.Schema
+	enricher *enricher.Enricher
+}
+
+func NewServer(e *enricher.Enricher) (*Server, error) {
+	schema, err := newSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the GraphQL schema: %w", err)
+	}
+	return &Server{schema: schema, enricher: e}, nil
+}
+
+// Execute runs a query. Every company and search is fetched at most once per query,
+// and the companies requested at the same depth are enriched as one batch. The
+// LinkedIn requests are cancelled with ctx, which may carry credentials set with
+// enricher.ContextWithCredentials.
+func (s *Server) Execute(ctx context.Context, req Request) *graphql.Result {
+	ctx = context.WithValue(ctx, loadersKey{}, &queryLoaders{
+		companies: newCompanyLoader(ctx, s.enricher),
+		searches:  newSearchLoader(ctx, s.enricher),
+	})
+	return graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+}
+
+type loadersKey struct{}
+
+// queryLoaders are the loaders of one query.
+type queryLoaders struct {
+	companies *companyLoader
+	searches  *searchLoader
+}
+
+func loaders(p graphql.ResolveParams) *queryLoaders {
+	return p.Context.Value(loadersKey{}).(*queryLoaders)
+}
@@ -0,0 +1,185 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/scraper"
+)
+
+// guestPage is the public page of Acme, listing Globex among its similar pages.
+const guestPage = `<html><body>
+<section class="top-card-layout"><h1 class="top-card-layout__title">Acme</h1></section>
+<dl><dt>Industry</dt><dd>Software</dd></dl>
+<section class="aside-section-container"><h2>Similar pages</h2><ul>
+<li><a href="/company/globex"><h3 class="base-aside-card__title">Globex</h3></a></li>
+</ul></section>
+</body></html>`
+
+// fakeLinkedIn answers the company pages: acme exists, down fails with 503 and every
+// other company is unknown. It counts the requests it receives.
+type fakeLinkedIn struct {
+	requests atomic.Int32
+}
+
+func (f *fakeLinkedIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	switch strings.Trim(r.URL.Path, "/") {
+	case "company/acme":
+		fmt.Fprint(w, guestPage)
+	case "company/down":
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestServer returns a GraphQL server whose enricher scrapes linkedin. The LinkedIn
+// requests are not retried and the breakers open after two failures.
+func newTestServer(t *testing.T, linkedin http.Handler) *Server {
+	t.Helper()
+	server := httptest.NewTLSServer(linkedin)
+	t.Cleanup(server.Close)
+
+	httpClient := req.C().EnableInsecureSkipVerify().SetDial(func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	})
+	s, err := NewServer(enricher.New(
+		enricher.WithHTTPClient(httpClient),
+		enricher.WithRetryPolicy(scraper.RetryPolicy{MaxAttempts: 1}),
+		enricher.WithBreakerPolicy(scraper.BreakerPolicy{FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, OpenFor: time.Minute, Probes: 1}),
+		enricher.WithLogger(log.New(io.Discard, "", 0)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// response is a GraphQL result as sent over HTTP.
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+// execute runs query and decodes its result from JSON, as a client would.
+func execute(t *testing.T, s *Server, query string, variables map[string]interface{}) response {
+	t.Helper()
+	raw, err := json.Marshal(s.Execute(context.Background(), Request{Query: query, Variables: variables}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp response
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatalf("decoding %s: %v", raw, err)
+	}
+	return resp
+}
+
+func TestCompanyQuery(t *testing.T) {
+	linkedin := &fakeLinkedIn{}
+	s := newTestServer(t, linkedin)
+
+	resp := execute(t, s, `query($slug: String!) {
+		company(slug: $slug) { slug name industry similarCompanies { slug company { name } } }
+		again: company(slug: "acme") { name }
+	}`, map[string]interface{}{"slug": "acme"})
+
+	company, _ := resp.Data["company"].(map[string]interface{})
+	if company["name"] != "Acme" || company["industry"] != "Software" || company["slug"] != "acme" {
+		t.Errorf("company = %v, want Acme in Software", company)
+	}
+	again, _ := resp.Data["again"].(map[string]interface{})
+	if again["name"] != "Acme" {
+		t.Errorf("aliased company = %v, want Acme", again)
+	}
+
+	// Globex is listed but unknown to LinkedIn: its company is null with a field error,
+	// the rest of the query still resolves.
+	similar, _ := company["similarCompanies"].([]interface{})
+	if len(similar) != 1 {
+		t.Fatalf("similarCompanies = %v, want Globex", company["similarCompanies"])
+	}
+	if globex := similar[0].(map[string]interface{}); globex["slug"] != "globex" || globex["company"] != nil {
+		t.Errorf("similar company = %v, want globex without a company", globex)
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "404") {
+		t.Fatalf("errors = %+v, want the 404 of globex", resp.Errors)
+	}
+	if path := fmt.Sprint(resp.Errors[0].Path); path != "[company similarCompanies 0 company]" {
+		t.Errorf("error path = %s, want the company of the similar page", path)
+	}
+
+	// acme is fetched once for both fields, globex once.
+	if got := linkedin.requests.Load(); got != 2 {
+		t.Errorf("LinkedIn got %d requests, want one per company", got)
+	}
+}
+
+func TestCompanyQueryNotFound(t *testing.T) {
+	s := newTestServer(t, &fakeLinkedIn{})
+
+	resp := execute(t, s, `{ companies(slugs: ["acme", "missing"]) { name } }`, nil)
+	companies, _ := resp.Data["companies"].([]interface{})
+	if len(companies) != 2 || companies[1] != nil {
+		t.Fatalf("companies = %v, want acme and null", resp.Data["companies"])
+	}
+	if acme, _ := companies[0].(map[string]interface{}); acme["name"] != "Acme" {
+		t.Errorf("first company = %v, want Acme", companies[0])
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "404") {
+		t.Errorf("errors = %+v, want the 404 of missing", resp.Errors)
+	}
+	if path := fmt.Sprint(resp.Errors[0].Path); path != "[companies 1]" {
+		t.Errorf("error path = %s, want [companies 1]", path)
+	}
+}
+
+func TestCompanyQueryCircuitOpen(t *testing.T) {
+	linkedin := &fakeLinkedIn{}
+	s := newTestServer(t, linkedin)
+
+	resp := execute(t, s, `{ first: company(slug: "down") { name } second: company(slug: "down2") { name } }`, nil)
+	if len(resp.Errors) != 2 {
+		t.Fatalf("errors = %+v, want the failures of down and down2", resp.Errors)
+	}
+	resp = execute(t, s, `{ companies(slugs: ["down"]) { name } }`, nil)
+	if len(resp.Errors) != 1 {
+		t.Fatalf("errors = %+v, want the failure of down", resp.Errors)
+	}
+
+	sent := linkedin.requests.Load()
+	resp = execute(t, s, `{ company(slug: "acme") { name } }`, nil)
+	if resp.Data["company"] != nil {
+		t.Errorf("company = %v with the breaker open, want null", resp.Data["company"])
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "circuit breaker") {
+		t.Errorf("errors = %+v, want the open circuit breaker", resp.Errors)
+	}
+	if linkedin.requests.Load() != sent {
+		t.Error("the open breaker let a request through to LinkedIn")
+	}
+}
+
+func TestEmptySlugIsRefused(t *testing.T) {
+	s := newTestServer(t, &fakeLinkedIn{})
+	resp := execute(t, s, `{ company(slug: "") { name } }`, nil)
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != errEmptySlug.Error() {
+		t.Errorf("errors = %+v, want %q", resp.Errors, errEmptySlug)
+	}
+}
@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/scraper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusFromError maps an error of the enricher to a gRPC status:
+//   - context cancellation and deadlines to Canceled and DeadlineExceeded,
+//   - a missing session cookie to Unauthenticated,
+//   - an unknown or ambiguous company to NotFound,
+//   - LinkedIn rate limiting (429, 999) to ResourceExhausted,
+//   - requests refused by an open circuit breaker to Unavailable,
+//   - other LinkedIn and network failures to Unavailable, so clients may retry,
+//   - everything else to Internal.
+func statusFromError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(errorCode(err), err.Error())
+}
+
+func errorCode(err error) codes.Code {
+	var statusErr *scraper.StatusError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, enricher.ErrNoSessionCookie):
+		return codes.Unauthenticated
+	case errors.Is(err, parser.ErrAmbiguousCompany):
+		return codes.NotFound
+	case errors.Is(err, scraper.ErrCircuitOpen):
+		return codes.Unavailable
+	case errors.As(err, &statusErr):
+		switch statusErr.StatusCode {
+		case http.StatusNotFound:
+			return codes.NotFound
+		case http.StatusTooManyRequests, scraper.StatusBlocked:
+			return codes.ResourceExhausted
+		}
+		return codes.Unavailable
+	case errors.As(err, &netErr):
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+// codeName returns the name of a code as written in the proto definitions, e.g.
+// "DEADLINE_EXCEEDED".
+func codeName(code codes.Code) string {
+	var name strings.Builder
+	previous := ' '
+	for _, r := range code.String() {
+		if unicode.IsUpper(r) && unicode.IsLower(previous) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+		previous = r
+	}
+	return name.String()
+}
@@ -0,0 +1,210 @@
+// Package grpcapi serves the enricher over gRPC, next to the REST routes. The service
+// is defined in proto/enricher/v1/enricher.proto.
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/grpcapi/enricherpb"
+	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Metadata keys carrying the credentials of a call, like the REST headers.
+const (
+	SessionCookieKey = "x-linkedin-session-cookie"
+	ProxyURLKey      = "x-proxy-url"
+)
+
+// MaxBatchSlugs is the largest number of companies a BatchEnrich call may request.
+const MaxBatchSlugs = 1000
+
+// Server implements the Enricher gRPC service.
+type Server struct {
+	enricherpb.UnimplementedEnricherServer
+	enricher *enricher.Enricher
+}
+
+func NewServer(e *enricher.Enricher) *Server {
+	return &Server{enricher: e}
+}
+
+// NewGRPCServer creates a gRPC server exposing the Enricher service and server reflection.
+func NewGRPCServer(e *enricher.Enricher, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	enricherpb.RegisterEnricherServer(server, NewServer(e))
+	reflection.Register(server)
+	return server
+}
+
+func (s *Server) EnrichCompany(ctx context.Context, req *enricherpb.EnrichCompanyRequest) (*enricherpb.CompanyResult, error) {
+	if req.GetSlug() == "" {
+		return nil, status.Error(codes.InvalidArgument, "company slug cannot be empty")
+	}
+	opts, err := enrichOptions(req.GetView(), req.GetFields())
+	if err != nil {
+		return nil, err
+	}
+	opts.Debug = req.GetDebug()
+
+	result, err := s.enricher.EnrichCompany(callContext(ctx), req.GetSlug(), opts)
+	if err != nil {
+		log.Printf("gRPC EnrichCompany %s: %v", req.GetSlug(), err)
+		return nil, statusFromError(err)
+	}
+	return companyResult(result)
+}
+
+// BatchEnrich enriches the companies in order and streams every outcome, so a slow or
+// failing company does not hold back the ones already enriched.
+func (s *Server) BatchEnrich(req *enricherpb.BatchEnrichRequest, stream grpc.ServerStreamingServer[enricherpb.BatchEnrichResponse]) error {
+	slugs := req.GetSlugs()
+	if len(slugs) == 0 {
+		return status.Error(codes.InvalidArgument, "slugs cannot be empty")
+	}
+	if len(slugs) > MaxBatchSlugs {
+		return status.Errorf(codes.InvalidArgument, "at most %d slugs can be enriched in one batch", MaxBatchSlugs)
+	}
+	opts, err := enrichOptions(req.GetView(), req.GetFields())
+	if err != nil {
+		return err
+	}
+
+	ctx := callContext(stream.Context())
+	for i, slug := range slugs {
+		if err := ctx.Err(); err != nil {
+			return statusFromError(err)
+		}
+
+		response := &enricherpb.BatchEnrichResponse{Index: int32(i), Slug: slug}
+		result, err := s.batchEnrichOne(ctx, slug, opts)
+		if err != nil {
+			st := status.Convert(err)
+			response.Outcome = &enricherpb.BatchEnrichResponse_Error{Error: &enricherpb.Error{
+				Code:    codeName(st.Code()),
+				Message: st.Message(),
+			}}
+		} else {
+			response.Outcome = &enricherpb.BatchEnrichResponse_Result{Result: result}
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) batchEnrichOne(ctx context.Context, slug string, opts enricher.EnrichOptions) (*enricherpb.CompanyResult, error) {
+	if slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "company slug cannot be empty")
+	}
+	result, err := s.enricher.EnrichCompany(ctx, slug, opts)
+	if err != nil {
+		log.Printf("gRPC BatchEnrich %s: %v", slug, err)
+		return nil, statusFromError(err)
+	}
+	return companyResult(result)
+}
+
+func (s *Server) SearchCompanies(ctx context.Context, req *enricherpb.SearchCompaniesRequest) (*enricherpb.SearchCompaniesResponse, error) {
+	if req.GetQuery() == "" {
+		return nil, status.Error(codes.InvalidArgument, "query cannot be empty")
+	}
+
+	results, err := s.enricher.SearchCompanies(callContext(ctx), req.GetQuery())
+	if err != nil {
+		log.Printf("gRPC SearchCompanies: %v", err)
+		return nil, statusFromError(err)
+	}
+
+	response := &enricherpb.SearchCompaniesResponse{Results: make([]*enricherpb.SearchResult, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, &enricherpb.SearchResult{
+			Id:   result.ID,
+			Name: result.Name,
+			Text: result.Text,
+		})
+	}
+	return response, nil
+}
+
+func (s *Server) ValidateSession(ctx context.Context, _ *enricherpb.ValidateSessionRequest) (*enricherpb.ValidateSessionResponse, error) {
+	valid, err := s.enricher.ValidateSession(callContext(ctx))
+	if err != nil {
+		log.Printf("gRPC ValidateSession: %v", err)
+		return nil, statusFromError(err)
+	}
+	return &enricherpb.ValidateSessionResponse{Valid: valid}, nil
+}
+
+// callContext carries the credentials sent in the metadata of a call to the enricher.
+func callContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return enricher.ContextWithCredentials(ctx, firstValue(md, SessionCookieKey), firstValue(md, ProxyURLKey))
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func enrichOptions(view string, fields []string) (enricher.EnrichOptions, error) {
+	if view == "" {
+		view = services.ViewSummary
+	}
+	if !services.IsValidView(view) {
+		return enricher.EnrichOptions{}, status.Error(codes.InvalidArgument, "view must be one of summary, detailed, raw")
+	}
+	return enricher.EnrichOptions{
+		View:   view,
+		Fields: utils.ParseFieldSet(strings.Join(fields, ",")),
+	}, nil
+}
+
+// companyResult converts a result to its message. The data and diagnostics go through
+// their JSON form, so they keep the field names of the REST API.
+func companyResult(result *enricher.CompanyResult) (*enricherpb.CompanyResult, error) {
+	message := &enricherpb.CompanyResult{
+		ScrapeType: result.ScrapeType,
+		View:       result.View,
+		Parser: &enricherpb.ParserInfo{
+			Strategy: result.Parser.Strategy,
+			Version:  result.Parser.Version,
+		},
+		Data:     &structpb.Value{},
+		Attempts: int32(result.Attempts),
+	}
+	if err := convertJSON(result.Data, message.Data); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode company data: %v", err)
+	}
+	if result.Diagnostics != nil {
+		message.Diagnostics = &structpb.Struct{}
+		if err := convertJSON(result.Diagnostics, message.Diagnostics); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode diagnostics: %v", err)
+		}
+	}
+	return message, nil
+}
+
+func convertJSON(value interface{}, message proto.Message) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return protojson.Unmarshal(raw, message)
+}
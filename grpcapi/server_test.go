@@ -0,0 +1,150 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/grpcapi/enricherpb"
+	"github.com/vit0-9/li-enricher-api/scraper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// guestPage is the public page of a company, enough for the guest strategy.
+const guestPage = `<html><body><section class="top-card-layout"><h1 class="top-card-layout__title">Acme</h1></section><dl><dt>Industry</dt><dd>Software</dd></dl></body></html>`
+
+// fakeLinkedIn answers the company pages: acme exists, down fails with 503 and every
+// other company is unknown. It counts the requests it receives.
+type fakeLinkedIn struct {
+	requests atomic.Int32
+}
+
+func (f *fakeLinkedIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	switch strings.Trim(r.URL.Path, "/") {
+	case "company/acme":
+		fmt.Fprint(w, guestPage)
+	case "company/down":
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newTestClient serves an enricher scraping linkedin over an in-memory gRPC connection.
+// The LinkedIn requests are not retried and the breakers open after two failures.
+func newTestClient(t *testing.T, linkedin http.Handler) enricherpb.EnricherClient {
+	t.Helper()
+	server := httptest.NewTLSServer(linkedin)
+	t.Cleanup(server.Close)
+
+	httpClient := req.C().EnableInsecureSkipVerify().SetDial(func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	})
+	e := enricher.New(
+		enricher.WithHTTPClient(httpClient),
+		enricher.WithRetryPolicy(scraper.RetryPolicy{MaxAttempts: 1}),
+		enricher.WithBreakerPolicy(scraper.BreakerPolicy{FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, OpenFor: time.Minute, Probes: 1}),
+		enricher.WithLogger(log.New(io.Discard, "", 0)),
+	)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := NewGRPCServer(e)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return enricherpb.NewEnricherClient(conn)
+}
+
+func TestEnrichCompanyRoundTrip(t *testing.T) {
+	client := newTestClient(t, &fakeLinkedIn{})
+	ctx := context.Background()
+
+	result, err := client.EnrichCompany(ctx, &enricherpb.EnrichCompanyRequest{Slug: "acme", Fields: []string{"name", "industry"}})
+	if err != nil {
+		t.Fatalf("EnrichCompany(acme): %v", err)
+	}
+	data := result.GetData().GetStructValue().AsMap()
+	if result.GetScrapeType() != "public" || data["name"] != "Acme" || data["industry"] != "Software" {
+		t.Errorf("EnrichCompany(acme) = %s %v, want the public Acme in Software", result.GetScrapeType(), data)
+	}
+	if len(data) != 2 {
+		t.Errorf("EnrichCompany(acme) returned %v, want only the requested fields", data)
+	}
+
+	_, err = client.EnrichCompany(ctx, &enricherpb.EnrichCompanyRequest{Slug: "missing"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("EnrichCompany(missing) = %v, want NotFound", err)
+	}
+
+	_, err = client.EnrichCompany(ctx, &enricherpb.EnrichCompanyRequest{})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("EnrichCompany without a slug = %v, want InvalidArgument", err)
+	}
+}
+
+func TestEnrichCompanyCircuitOpen(t *testing.T) {
+	linkedin := &fakeLinkedIn{}
+	client := newTestClient(t, linkedin)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.EnrichCompany(ctx, &enricherpb.EnrichCompanyRequest{Slug: "down"})
+		if code := status.Code(err); code != codes.Unavailable {
+			t.Fatalf("EnrichCompany(down) = %v, want Unavailable", err)
+		}
+	}
+
+	sent := linkedin.requests.Load()
+	_, err := client.EnrichCompany(ctx, &enricherpb.EnrichCompanyRequest{Slug: "acme"})
+	if code := status.Code(err); code != codes.Unavailable || !strings.Contains(err.Error(), "circuit breaker") {
+		t.Fatalf("EnrichCompany(acme) with the breaker open = %v, want Unavailable from the breaker", err)
+	}
+	if linkedin.requests.Load() != sent {
+		t.Error("the open breaker let a request through to LinkedIn")
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{fmt.Errorf("scrape: %w", context.Canceled), codes.Canceled},
+		{enricher.ErrNoSessionCookie, codes.Unauthenticated},
+		{&scraper.StatusError{StatusCode: http.StatusNotFound}, codes.NotFound},
+		{&scraper.StatusError{StatusCode: http.StatusTooManyRequests}, codes.ResourceExhausted},
+		{&scraper.StatusError{StatusCode: scraper.StatusBlocked}, codes.ResourceExhausted},
+		{&scraper.StatusError{StatusCode: http.StatusBadGateway}, codes.Unavailable},
+		{&scraper.CircuitOpenError{Breaker: "target:company", RetryIn: time.Minute}, codes.Unavailable},
+		{fmt.Errorf("parse failed"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("errorCode(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeType is the kind of a Change.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// Change is one difference between two enrichment results. Path is the dotted path of
+// the field; elements of keyed lists are addressed as list[key], or list[#index] when
+// they are matched by position.
+type Change struct {
+	Path string      `json:"path"`
+	Type ChangeType  `json:"type"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// listKeys identifies the elements of the lists of a company summary, so that list diffs
+// report added and removed elements instead of positional changes. Lists without an entry
+// are keyed by the canonical JSON of their elements.
+var listKeys = map[string][]string{
+	"office_locations":                          {"line1", "line2", "city", "state", "postal_code", "country"},
+	"similar_companies":                         {"slug", "name"},
+	"phone_numbers":                             {"e164", "raw"},
+	"social_profiles":                           {"url"},
+	"funding_summary.rounds":                    {"announced_on", "type"},
+	"funding_summary.last_round.lead_investors": {"name"},
+}
+
+// Diff compares two enrichment results field by field.
+func Diff(oldData, newData json.RawMessage) ([]Change, error) {
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal(oldData, &oldValue); err != nil {
+		return nil, fmt.Errorf("failed to decode old data: %w", err)
+	}
+	if err := json.Unmarshal(newData, &newValue); err != nil {
+		return nil, fmt.Errorf("failed to decode new data: %w", err)
+	}
+
+	changes := []Change{}
+	diffValues("", "", oldValue, newValue, &changes)
+	return changes, nil
+}
+
+// diffValues appends the changes between two decoded JSON values. path is the location
+// reported in changes, schemaPath the same location without list keys, used to look up listKeys.
+func diffValues(path, schemaPath string, oldValue, newValue interface{}, changes *[]Change) {
+	switch {
+	case isEmpty(oldValue) && isEmpty(newValue):
+		return
+	case isEmpty(oldValue):
+		*changes = append(*changes, Change{Path: path, Type: ChangeAdded, New: newValue})
+		return
+	case isEmpty(newValue):
+		*changes = append(*changes, Change{Path: path, Type: ChangeRemoved, Old: oldValue})
+		return
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		for _, key := range unionKeys(oldMap, newMap) {
+			diffValues(joinPath(path, key), joinPath(schemaPath, key), oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+	if oldIsList && newIsList {
+		diffLists(path, schemaPath, oldList, newList, changes)
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{Path: path, Type: ChangeModified, Old: oldValue, New: newValue})
+	}
+}
+
+// diffLists matches elements by key: unmatched elements are added or removed, matched
+// object elements are compared field by field. Order changes are ignored, except for the
+// elements matched by position (see indexList), which are compared whatever their kind.
+func diffLists(path, schemaPath string, oldList, newList []interface{}, changes *[]Change) {
+	keyFields := listKeys[schemaPath]
+	oldIndex := indexList(oldList, keyFields)
+	newIndex := indexList(newList, keyFields)
+
+	for _, key := range oldIndex.order {
+		if _, ok := newIndex.byKey[key]; !ok {
+			*changes = append(*changes, Change{Path: path, Type: ChangeRemoved, Old: oldIndex.byKey[key]})
+		}
+	}
+	for _, key := range newIndex.order {
+		oldElement, ok := oldIndex.byKey[key]
+		if !ok {
+			*changes = append(*changes, Change{Path: path, Type: ChangeAdded, New: newIndex.byKey[key]})
+			continue
+		}
+		if keyFields != nil || newIndex.positional[key] {
+			diffValues(fmt.Sprintf("%s[%s]", path, key), schemaPath, oldElement, newIndex.byKey[key], changes)
+		}
+	}
+}
+
+// listIndex holds the elements of a list by key, in list order.
+type listIndex struct {
+	byKey      map[string]interface{}
+	order      []string
+	positional map[string]bool
+}
+
+// indexList keys the elements of a list. Elements without any key field, and the repeats
+// of a key already seen, are keyed by their position ("#2") so that they are still
+// compared rather than merged into another element.
+func indexList(list []interface{}, keyFields []string) listIndex {
+	index := listIndex{
+		byKey:      make(map[string]interface{}, len(list)),
+		order:      make([]string, 0, len(list)),
+		positional: map[string]bool{},
+	}
+	for i, element := range list {
+		key := elementKey(element, keyFields)
+		if _, exists := index.byKey[key]; exists || key == "" && keyFields != nil {
+			key = fmt.Sprintf("#%d", i)
+			index.positional[key] = true
+		}
+		index.byKey[key] = element
+		index.order = append(index.order, key)
+	}
+	return index
+}
+
+func elementKey(element interface{}, keyFields []string) string {
+	if obj, ok := element.(map[string]interface{}); ok && keyFields != nil {
+		parts := make([]string, 0, len(keyFields))
+		for _, field := range keyFields {
+			value, _ := obj[field].(string)
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				parts = append(parts, value)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	// encoding/json sorts map keys, so this is canonical. Strings are quoted, so they
+	// never collide with positional keys.
+	raw, _ := json.Marshal(element)
+	return string(raw)
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isEmpty treats missing, null and empty values alike, as the summaries omit empty fields.
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
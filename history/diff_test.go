@@ -0,0 +1,53 @@
+package history
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func diffJSON(t *testing.T, oldData, newData string) []Change {
+	t.Helper()
+	changes, err := Diff(json.RawMessage(oldData), json.RawMessage(newData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
+
+func TestDiffKeyedList(t *testing.T) {
+	changes := diffJSON(t,
+		`{"social_profiles": [{"url": "https://x.com/acme"}, {"url": "https://github.com/acme"}]}`,
+		`{"social_profiles": [{"url": "https://github.com/acme"}, {"url": "https://youtube.com/acme"}]}`,
+	)
+	if len(changes) != 2 || changes[0].Type != ChangeRemoved || changes[1].Type != ChangeAdded {
+		t.Errorf("changes = %+v, want one removed and one added profile", changes)
+	}
+}
+
+func TestDiffListElementsWithoutKeyFields(t *testing.T) {
+	changes := diffJSON(t,
+		`{"office_locations": [{"headquarter": true}, {"headquarter": false}]}`,
+		`{"office_locations": [{"headquarter": false}, {"headquarter": false}]}`,
+	)
+	if len(changes) != 1 {
+		t.Fatalf("changes = %+v, want one change", changes)
+	}
+	if changes[0].Path != "office_locations[#0].headquarter" || changes[0].Type != ChangeModified {
+		t.Errorf("change = %+v, want office_locations[#0].headquarter modified", changes[0])
+	}
+}
+
+func TestDiffListDuplicateKeys(t *testing.T) {
+	changes := diffJSON(t,
+		`{"office_locations": [{"city": "Paris", "line1": "1 rue A"}, {"city": "Paris", "line1": "1 rue A"}]}`,
+		`{"office_locations": [{"city": "Paris", "line1": "1 rue A"}, {"city": "Paris", "line1": "1 rue A", "headquarter": true}]}`,
+	)
+	if len(changes) != 1 || changes[0].Path != "office_locations[#1].headquarter" || changes[0].Type != ChangeAdded {
+		t.Errorf("changes = %+v, want office_locations[#1].headquarter added", changes)
+	}
+
+	changes = diffJSON(t, `{"specialities": ["Search", "Search"]}`, `{"specialities": ["Search"]}`)
+	if len(changes) != 1 || changes[0].Type != ChangeRemoved || changes[0].Old != "Search" {
+		t.Errorf("changes = %+v, want the repeated speciality removed", changes)
+	}
+}
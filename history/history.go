@@ -0,0 +1,53 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/parser"
+)
+
+// ErrNotFound is returned when no snapshot matches a lookup.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Snapshot is a successful enrichment result persisted at a point in time.
+type Snapshot struct {
+	ID         int64               `json:"id"`
+	Slug       string              `json:"slug"`
+	CapturedAt time.Time           `json:"captured_at"`
+	ScrapeType string              `json:"scrapeType"`
+	Parser     parser.StrategyInfo `json:"parser"`
+	Data       json.RawMessage     `json:"data,omitempty" swaggertype:"object"`
+}
+
+// Store persists snapshots of enriched companies.
+type Store interface {
+	// Save stores the snapshot and sets its ID.
+	Save(snapshot *Snapshot) error
+	// List returns the snapshots of a company, newest first. A limit <= 0 returns all of them.
+	List(slug string, limit int) ([]Snapshot, error)
+	// ListInfo returns a page of the snapshots of a company without their data, newest
+	// first, skipping the first offset ones. A limit <= 0 returns all the remaining ones.
+	ListInfo(slug string, limit, offset int) ([]Snapshot, error)
+	// Get returns a snapshot of a company by ID.
+	Get(slug string, id int64) (*Snapshot, error)
+	// AsOf returns the latest snapshot of a company captured at or before the given time.
+	AsOf(slug string, at time.Time) (*Snapshot, error)
+	// Previous returns the latest snapshot of a company listed after snapshot id by List,
+	// of the given scrape type unless empty.
+	Previous(slug string, id int64, scrapeType string) (*Snapshot, error)
+	// SaveAlias records that alias, such as the numeric ID of a company, names the
+	// company whose snapshots are keyed by slug.
+	SaveAlias(alias, slug string) error
+	// Resolve returns the slug the snapshots of a company identifier are keyed by: the
+	// slug recorded for it by SaveAlias, else the normalized identifier.
+	Resolve(identifier string) (string, error)
+	Close() error
+}
+
+// NormalizeSlug is the form under which a company's snapshots are keyed.
+func NormalizeSlug(slug string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(slug), "/"))
+}
@@ -0,0 +1,140 @@
+package history
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping snapshots in memory, for development and short-lived processes.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	nextID    int64
+	snapshots map[string][]Snapshot // Oldest first.
+	aliases   map[string]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: map[string][]Snapshot{}, aliases: map[string]string{}}
+}
+
+func (s *MemoryStore) Save(snapshot *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	snapshot.ID = s.nextID
+	slug := NormalizeSlug(snapshot.Slug)
+	stored := *snapshot
+	stored.Slug = slug
+	stored.CapturedAt = snapshot.CapturedAt.UTC()
+
+	list := append(s.snapshots[slug], stored)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CapturedAt.Before(list[j].CapturedAt)
+	})
+	s.snapshots[slug] = list
+	return nil
+}
+
+func (s *MemoryStore) List(slug string, limit int) ([]Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.snapshots[NormalizeSlug(slug)]
+	snapshots := []Snapshot{}
+	for i := len(list) - 1; i >= 0; i-- {
+		if limit > 0 && len(snapshots) == limit {
+			break
+		}
+		snapshots = append(snapshots, list[i])
+	}
+	return snapshots, nil
+}
+
+func (s *MemoryStore) ListInfo(slug string, limit, offset int) ([]Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.snapshots[NormalizeSlug(slug)]
+	snapshots := []Snapshot{}
+	for i := len(list) - 1 - max(offset, 0); i >= 0; i-- {
+		if limit > 0 && len(snapshots) == limit {
+			break
+		}
+		snapshot := list[i]
+		snapshot.Data = nil
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (s *MemoryStore) Get(slug string, id int64) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, snapshot := range s.snapshots[NormalizeSlug(slug)] {
+		if snapshot.ID == id {
+			return &snapshot, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) AsOf(slug string, at time.Time) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.snapshots[NormalizeSlug(slug)]
+	for i := len(list) - 1; i >= 0; i-- {
+		if !list[i].CapturedAt.After(at) {
+			snapshot := list[i]
+			return &snapshot, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) Previous(slug string, id int64, scrapeType string) (*Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.snapshots[NormalizeSlug(slug)]
+	found := false
+	for i := len(list) - 1; i >= 0; i-- {
+		switch {
+		case !found:
+			found = list[i].ID == id
+		case scrapeType == "" || list[i].ScrapeType == scrapeType:
+			snapshot := list[i]
+			return &snapshot, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) SaveAlias(alias, slug string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alias, slug = NormalizeSlug(alias), NormalizeSlug(slug)
+	if alias != slug {
+		s.aliases[alias] = slug
+	}
+	return nil
+}
+
+func (s *MemoryStore) Resolve(identifier string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	identifier = NormalizeSlug(identifier)
+	if slug, ok := s.aliases[identifier]; ok {
+		return slug, nil
+	}
+	return identifier, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
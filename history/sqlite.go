@@ -0,0 +1,229 @@
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS company_snapshots (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	slug            TEXT    NOT NULL,
+	captured_at     INTEGER NOT NULL,
+	scrape_type     TEXT    NOT NULL,
+	parser_strategy TEXT    NOT NULL,
+	parser_version  TEXT    NOT NULL,
+	data            TEXT    NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_company_snapshots_slug_captured_at
+	ON company_snapshots (slug, captured_at);
+CREATE TABLE IF NOT EXISTS company_aliases (
+	alias TEXT PRIMARY KEY,
+	slug  TEXT NOT NULL
+);
+`
+
+// SQLiteDSN returns the data source name of a database file, waiting for locks instead
+// of failing right away so that several stores can share the file.
+func SQLiteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return "file:" + path + separator + "_pragma=busy_timeout(5000)"
+}
+
+// SQLiteStore is a Store backed by a SQLite database file.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (and creates if needed) the SQLite database at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", SQLiteDSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot database: %w", err)
+	}
+	// SQLite allows a single writer; serializing access avoids "database is locked" errors.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create snapshot schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Save(snapshot *Snapshot) error {
+	res, err := s.db.Exec(
+		`INSERT INTO company_snapshots (slug, captured_at, scrape_type, parser_strategy, parser_version, data)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		NormalizeSlug(snapshot.Slug),
+		snapshot.CapturedAt.UTC().UnixNano(),
+		snapshot.ScrapeType,
+		snapshot.Parser.Strategy,
+		snapshot.Parser.Version,
+		string(snapshot.Data),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	snapshot.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *SQLiteStore) List(slug string, limit int) ([]Snapshot, error) {
+	if limit <= 0 {
+		limit = -1 // No limit in SQLite.
+	}
+	rows, err := s.db.Query(
+		`SELECT id, slug, captured_at, scrape_type, parser_strategy, parser_version, data
+		 FROM company_snapshots WHERE slug = ? ORDER BY captured_at DESC, id DESC LIMIT ?`,
+		NormalizeSlug(slug), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+func (s *SQLiteStore) ListInfo(slug string, limit, offset int) ([]Snapshot, error) {
+	if limit <= 0 {
+		limit = -1 // No limit in SQLite.
+	}
+	rows, err := s.db.Query(
+		`SELECT id, slug, captured_at, scrape_type, parser_strategy, parser_version, ''
+		 FROM company_snapshots WHERE slug = ? ORDER BY captured_at DESC, id DESC LIMIT ? OFFSET ?`,
+		NormalizeSlug(slug), limit, max(offset, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Data = nil
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+func (s *SQLiteStore) Get(slug string, id int64) (*Snapshot, error) {
+	row := s.db.QueryRow(
+		`SELECT id, slug, captured_at, scrape_type, parser_strategy, parser_version, data
+		 FROM company_snapshots WHERE slug = ? AND id = ?`,
+		NormalizeSlug(slug), id,
+	)
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return snapshot, err
+}
+
+func (s *SQLiteStore) AsOf(slug string, at time.Time) (*Snapshot, error) {
+	row := s.db.QueryRow(
+		`SELECT id, slug, captured_at, scrape_type, parser_strategy, parser_version, data
+		 FROM company_snapshots WHERE slug = ? AND captured_at <= ? ORDER BY captured_at DESC, id DESC LIMIT 1`,
+		NormalizeSlug(slug), at.UTC().UnixNano(),
+	)
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return snapshot, err
+}
+
+func (s *SQLiteStore) Previous(slug string, id int64, scrapeType string) (*Snapshot, error) {
+	row := s.db.QueryRow(
+		`SELECT s.id, s.slug, s.captured_at, s.scrape_type, s.parser_strategy, s.parser_version, s.data
+		 FROM company_snapshots s JOIN company_snapshots ref ON ref.id = ? AND ref.slug = s.slug
+		 WHERE s.slug = ? AND (? = '' OR s.scrape_type = ?)
+		   AND (s.captured_at < ref.captured_at OR (s.captured_at = ref.captured_at AND s.id < ref.id))
+		 ORDER BY s.captured_at DESC, s.id DESC LIMIT 1`,
+		id, NormalizeSlug(slug), scrapeType, scrapeType,
+	)
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return snapshot, err
+}
+
+func (s *SQLiteStore) SaveAlias(alias, slug string) error {
+	alias, slug = NormalizeSlug(alias), NormalizeSlug(slug)
+	if alias == slug {
+		return nil
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO company_aliases (alias, slug) VALUES (?, ?)
+		 ON CONFLICT (alias) DO UPDATE SET slug = excluded.slug`,
+		alias, slug,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save company alias: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Resolve(identifier string) (string, error) {
+	identifier = NormalizeSlug(identifier)
+	var slug string
+	err := s.db.QueryRow(`SELECT slug FROM company_aliases WHERE alias = ?`, identifier).Scan(&slug)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return identifier, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to resolve company alias: %w", err)
+	}
+	return slug, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSnapshot(row scanner) (*Snapshot, error) {
+	var snapshot Snapshot
+	var capturedAt int64
+	var data string
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.Slug,
+		&capturedAt,
+		&snapshot.ScrapeType,
+		&snapshot.Parser.Strategy,
+		&snapshot.Parser.Version,
+		&data,
+	)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.CapturedAt = time.Unix(0, capturedAt).UTC()
+	snapshot.Data = []byte(data)
+	return &snapshot, nil
+}
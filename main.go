@@ -4,12 +4,8 @@ import (
 	"log"
 	"os"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/swagger"
 	"github.com/joho/godotenv"
-	"github.com/vit0-9/li-enricher-api/routes"
-
-	_ "github.com/vit0-9/li-enricher-api/docs"
+	"github.com/vit0-9/li-enricher-api/server"
 )
 
 // @title           LinkedIn Enricher API
@@ -27,13 +23,7 @@ func main() {
 	if port == "" {
 		port = "3000"
 	}
-	app := fiber.New()
-
-	app.Get("/swagger/*", swagger.HandlerDefault)
-
-	routes.Setup(app)
-
-	log.Println("Starting server on http://localhost:" + port)
-	log.Println("API documentation available at http://localhost:" + port + "/swagger/index.html")
-	log.Fatal(app.Listen(":" + port))
+	if err := server.Run(port); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// BlockDiagnostics describes one bpr-guid <code> block of a page.
+type BlockDiagnostics struct {
+	ID               string   `json:"id"`
+	Size             int      `json:"size"`
+	ValidJSON        bool     `json:"valid_json"`
+	PassedValidation bool     `json:"passed_validation"`
+	TopLevelKeys     []string `json:"top_level_keys,omitempty"`
+}
+
+// StrategyDiagnostics is the outcome of one registered strategy against a page.
+type StrategyDiagnostics struct {
+	StrategyInfo
+	Skipped bool   `json:"skipped,omitempty"`
+	Matched bool   `json:"matched"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Diagnostics explains what a page contains and which extraction paths match it.
+type Diagnostics struct {
+	HTMLSize           int                   `json:"html_size"`
+	FinalURL           string                `json:"final_url,omitempty"`
+	BprGuidBlocksFound int                   `json:"bpr_guid_blocks_found"`
+	BprGuidBlocksValid int                   `json:"bpr_guid_blocks_valid"`
+	BprGuidBlocks      []BlockDiagnostics    `json:"bpr_guid_blocks"`
+	LdJSONFound        bool                  `json:"ld_json_found"`
+	Strategies         []StrategyDiagnostics `json:"strategies"`
+}
+
+// Diagnose inspects the bpr-guid blocks and ld+json script of the HTML and runs every
+// registered strategy against it, without stopping at the first match.
+func Diagnose(htmlContent, identifier string, authenticated bool) *Diagnostics {
+	diagnostics := &Diagnostics{
+		HTMLSize:      len(htmlContent),
+		BprGuidBlocks: []BlockDiagnostics{},
+		Strategies:    []StrategyDiagnostics{},
+	}
+
+	page, err := newPage(htmlContent, identifier, authenticated)
+	if err != nil {
+		return diagnostics
+	}
+
+	page.Doc.Find(`code[id^="bpr-guid"]`).Each(func(i int, s *goquery.Selection) {
+		rawJSON := s.Text()
+		block := BlockDiagnostics{Size: len(rawJSON)}
+		block.ID, _ = s.Attr("id")
+
+		var parsedJSON map[string]interface{}
+		if rawJSON != "" && json.Unmarshal([]byte(rawJSON), &parsedJSON) == nil {
+			block.ValidJSON = true
+			block.PassedValidation = isJSONValid(parsedJSON)
+			for key := range parsedJSON {
+				block.TopLevelKeys = append(block.TopLevelKeys, key)
+			}
+			sort.Strings(block.TopLevelKeys)
+		}
+		if block.PassedValidation {
+			diagnostics.BprGuidBlocksValid++
+		}
+		diagnostics.BprGuidBlocks = append(diagnostics.BprGuidBlocks, block)
+	})
+	diagnostics.BprGuidBlocksFound = len(diagnostics.BprGuidBlocks)
+	diagnostics.LdJSONFound = page.Doc.Find("script[type='application/ld+json']").Length() > 0
+
+	for _, strategy := range Strategies() {
+		outcome := StrategyDiagnostics{StrategyInfo: StrategyInfo{Strategy: strategy.Name, Version: strategy.Version}}
+		if strategy.RequiresSession && !authenticated {
+			outcome.Skipped = true
+		} else if _, err := strategy.Extract(page); err != nil {
+			outcome.Error = err.Error()
+		} else {
+			outcome.Matched = true
+		}
+		diagnostics.Strategies = append(diagnostics.Strategies, outcome)
+	}
+
+	return diagnostics
+}
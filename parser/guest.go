@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+var followersPattern = regexp.MustCompile(`(?i)([\d.,]+)\s*([KM]?)\s+followers`)
+
+// ExtractGuestCompanyData parses the about section (dl/dt/dd blocks) and the top card
+// of the public, cookie-less company page. The ld+json data of the page, when present,
+// fills the fields the guest markup doesn't carry.
+func ExtractGuestCompanyData(htmlContent string) (*LiCompany, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML for guest about section: %w", err)
+	}
+	return extractGuestCompanyData(doc)
+}
+
+func extractGuestCompanyData(doc *goquery.Document) (*LiCompany, error) {
+	company := &LiCompany{}
+	found := false
+
+	doc.Find("dl dt").Each(func(i int, dt *goquery.Selection) {
+		dd := dt.NextFilteredUntil("dd", "dt")
+		value := cleanText(dd.Text())
+		if value == "" {
+			return
+		}
+		// The visible website text may be a shortened link; the href carries the
+		// redirect-wrapped target.
+		if href, ok := dd.Find("a[href]").Attr("href"); ok && strings.EqualFold(cleanText(dt.Text()), "website") {
+			value = href
+		}
+		if applyAboutEntry(company, cleanText(dt.Text()), value) {
+			found = true
+		}
+	})
+
+	if !found {
+		return nil, fmt.Errorf("could not find the about section in the guest HTML")
+	}
+	log.Println("✅ Found the about section in the guest HTML.")
+
+	if description := cleanText(doc.Find(`[data-test-id="about-us__description"]`).First().Text()); description != "" {
+		company.Description = description
+	}
+
+	topCard := doc.Find(".top-card-layout").First()
+	if name := cleanText(topCard.Find(".top-card-layout__title").First().Text()); name != "" {
+		company.Name = name
+	}
+	if slogan := cleanText(topCard.Find(".top-card-layout__headline").First().Text()); slogan != "" {
+		company.Slogan = slogan
+	}
+	if match := followersPattern.FindStringSubmatch(topCard.Text()); match != nil {
+		company.FollowerCount = parseCount(match[1], match[2])
+	}
+
+	company.SimilarCompanies = extractGuestSimilarCompanies(doc)
+
+	// Complete the result with the ld+json data, which is usually present on the same page.
+	if ldData, err := extractLdJSONData(doc); err == nil {
+		mergeLiCompany(company, ldData)
+	}
+	for i, phone := range company.PhoneNumbers {
+		company.PhoneNumbers[i] = utils.NormalizePhoneNumber(phone.Raw, company.HeadquartersCountry)
+	}
+
+	return company, nil
+}
+
+// rawGuestMarkup returns the untouched guest markup the guest strategy reads: the top
+// card, the about section and description, and the ld+json document completing them.
+// Sections missing from the page are left out.
+func rawGuestMarkup(page *Page, data interface{}) interface{} {
+	raw := map[string]interface{}{}
+	sections := map[string]*goquery.Selection{
+		"top_card":    page.Doc.Find(".top-card-layout").First(),
+		"about":       page.Doc.Find("dl"),
+		"description": page.Doc.Find(`[data-test-id="about-us__description"]`).First(),
+	}
+	for name, selection := range sections {
+		if selection.Length() == 0 {
+			continue
+		}
+		var markup strings.Builder
+		selection.Each(func(i int, s *goquery.Selection) {
+			if html, err := goquery.OuterHtml(s); err == nil {
+				markup.WriteString(html)
+			}
+		})
+		raw[name] = markup.String()
+	}
+	if ldJSON := rawLdJSON(page, data); ldJSON != nil {
+		raw["ld+json"] = ldJSON
+	}
+	return raw
+}
+
+// applyAboutEntry maps a dt label of the about section to a LiCompany field.
+func applyAboutEntry(company *LiCompany, label, value string) bool {
+	switch strings.ToLower(label) {
+	case "website":
+		// Some companies list a social profile as their website.
+		var profiles []SocialProfile
+		company.Website, profiles = classifyLinks([]string{value})
+		for _, profile := range profiles {
+			if !containsProfile(company.SocialProfiles, profile) {
+				company.SocialProfiles = append(company.SocialProfiles, profile)
+			}
+		}
+	case "industry", "industries":
+		company.Industry = value
+	case "company size":
+		company.CompanySize = strings.TrimSpace(strings.TrimSuffix(value, "employees"))
+	case "headquarters":
+		company.Headquarters = value
+	case "type", "organization type":
+		company.CompanyType = utils.NormalizeCompanyType(value)
+	case "founded":
+		if year, err := strconv.Atoi(value); err == nil {
+			company.FoundedYear = year
+		}
+	case "phone":
+		company.PhoneNumbers = append(company.PhoneNumbers, utils.PhoneNumber{Raw: value})
+	case "specialties", "specialities":
+		company.Specialities = splitSpecialities(value)
+	default:
+		return false
+	}
+	return true
+}
+
+// mergeLiCompany copies the fields of src that are not set in dst.
+func mergeLiCompany(dst, src *LiCompany) {
+	if dst.Name == "" {
+		dst.Name = src.Name
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if dst.Website == "" {
+		dst.Website = src.Website
+	}
+	for _, profile := range src.SocialProfiles {
+		if !containsProfile(dst.SocialProfiles, profile) {
+			dst.SocialProfiles = append(dst.SocialProfiles, profile)
+		}
+	}
+	if dst.Slogan == "" {
+		dst.Slogan = src.Slogan
+	}
+	if dst.EmployeeCount == nil {
+		dst.EmployeeCount = src.EmployeeCount
+	}
+	if dst.Headquarters == "" {
+		dst.Headquarters = src.Headquarters
+	}
+	if dst.HeadquartersCountry == "" {
+		dst.HeadquartersCountry = src.HeadquartersCountry
+	}
+	if dst.Stock == nil {
+		dst.Stock = src.Stock
+	}
+	for _, phone := range src.PhoneNumbers {
+		if !containsPhone(dst.PhoneNumbers, phone) {
+			dst.PhoneNumbers = append(dst.PhoneNumbers, phone)
+		}
+	}
+}
+
+func containsProfile(profiles []SocialProfile, profile SocialProfile) bool {
+	for _, p := range profiles {
+		if p.URL == profile.URL {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPhone(phones []utils.PhoneNumber, phone utils.PhoneNumber) bool {
+	for _, p := range phones {
+		if p.Raw == phone.Raw {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSpecialities splits "Search, ads, Mobile, and Android" into its items.
+func splitSpecialities(value string) []string {
+	var specialities []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		item = strings.TrimSpace(strings.TrimPrefix(item, "and "))
+		if item != "" && item != "and" {
+			specialities = append(specialities, item)
+		}
+	}
+	return specialities
+}
+
+// parseCount parses follower counts such as "1,234,567" or "12.5" with a "K" suffix.
+func parseCount(number, suffix string) int {
+	multiplier := 1.0
+	switch strings.ToUpper(suffix) {
+	case "K":
+		multiplier = 1e3
+	case "M":
+		multiplier = 1e6
+	}
+	if multiplier == 1 {
+		number = strings.NewReplacer(",", "", ".", "").Replace(number)
+	} else {
+		number = strings.ReplaceAll(number, ",", ".")
+	}
+	count, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	return int(count * multiplier)
+}
+
+// cleanText collapses the whitespace LinkedIn's templates put around text nodes.
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
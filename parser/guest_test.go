@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the parser tests")
+
+// TestExtractGuestCompanyDataGolden parses the guest pages of testdata and compares
+// the companies with their .golden.json files. Run with -update to rewrite them.
+func TestExtractGuestCompanyDataGolden(t *testing.T) {
+	tests := []struct {
+		page    string
+		wantErr bool
+	}{
+		{"guest_company", false},
+		// Unclosed tags, dt without dd, empty values and broken ld+json are skipped.
+		{"guest_malformed", false},
+		// A page without any known about entry is not a guest company page.
+		{"guest_no_about", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			html, err := os.ReadFile(filepath.Join("testdata", tt.page+".html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			company, err := ExtractGuestCompanyData(string(html))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsed %+v, want an error", company)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.MarshalIndent(company, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", tt.page+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(got)) != strings.TrimSpace(string(want)) {
+				t.Errorf("company differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestApplyAboutEntry(t *testing.T) {
+	tests := []struct {
+		label, value string
+		want         LiCompany
+		known        bool
+	}{
+		{"Industry", "Software", LiCompany{Industry: "Software"}, true},
+		{"INDUSTRIES", "Software", LiCompany{Industry: "Software"}, true},
+		{"Company size", "11-50 employees", LiCompany{CompanySize: "11-50"}, true},
+		{"Founded", "1998", LiCompany{FoundedYear: 1998}, true},
+		{"Founded", "circa 1998", LiCompany{}, true},
+		{"Employees on LinkedIn", "120", LiCompany{}, false},
+	}
+	for _, tt := range tests {
+		var company LiCompany
+		known := applyAboutEntry(&company, tt.label, tt.value)
+		if known != tt.known || company.Industry != tt.want.Industry || company.CompanySize != tt.want.CompanySize || company.FoundedYear != tt.want.FoundedYear {
+			t.Errorf("applyAboutEntry(%q, %q) = %v, %+v; want %v, %+v", tt.label, tt.value, known, company, tt.known, tt.want)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		number, suffix string
+		want           int
+	}{
+		{"12,345", "", 12345},
+		{"1.234.567", "", 1234567},
+		{"2.5", "K", 2500},
+		{"2,5", "k", 2500},
+		{"1", "M", 1000000},
+		{"lots", "", 0},
+	}
+	for _, tt := range tests {
+		if got := parseCount(tt.number, tt.suffix); got != tt.want {
+			t.Errorf("parseCount(%q, %q) = %d, want %d", tt.number, tt.suffix, got, tt.want)
+		}
+	}
+}
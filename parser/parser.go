@@ -2,22 +2,45 @@ package parser
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/vit0-9/li-enricher-api/utils"
 )
 
 // LiCompany holds the extracted company data from the LD+JSON block.
 // Using a struct provides better type safety and clarity.
 type LiCompany struct {
-	Name          string `json:"name,omitempty"`
-	Description   string `json:"description,omitempty"`
-	Website       any    `json:"website,omitempty"`
-	Slogan        string `json:"slogan,omitempty"`
-	EmployeeCount any    `json:"employee_count,omitempty"`
-	Headquarters  string `json:"headquarters,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	Website        string          `json:"website,omitempty"`
+	SocialProfiles []SocialProfile `json:"social_profiles,omitempty"`
+	Slogan         string          `json:"slogan,omitempty"`
+	EmployeeCount  any             `json:"employee_count,omitempty"`
+	Headquarters   string          `json:"headquarters,omitempty"`
+	// HeadquartersCountry is the ISO country code of the headquarters, also used as
+	// the default region of phone numbers.
+	HeadquartersCountry string              `json:"headquarters_country,omitempty"`
+	PhoneNumbers        []utils.PhoneNumber `json:"phone_numbers,omitempty"`
+	Industry            string              `json:"industry,omitempty"`
+	CompanySize         string              `json:"company_size,omitempty"`
+	CompanyType         string              `json:"company_type,omitempty"`
+	Stock               *Stock              `json:"stock,omitempty"`
+	FoundedYear         int                 `json:"founded_year,omitempty"`
+	Specialities        []string            `json:"specialities,omitempty"`
+	FollowerCount       int                 `json:"follower_count,omitempty"`
+	// SimilarCompanies is only filled from the guest page markup.
+	SimilarCompanies []SimilarCompany `json:"similar_companies,omitempty"`
+}
+
+// Stock holds the listing details of a public company.
+type Stock struct {
+	Symbol   string `json:"symbol"`
+	Exchange string `json:"exchange,omitempty"`
 }
 
 type LdJSON struct {
@@ -45,16 +68,34 @@ func isJSONValid(data map[string]interface{}) bool {
 	return ok
 }
 
+// ErrAmbiguousCompany is returned when a page carries company JSON blocks but
+// none of them can be tied to the requested company.
+var ErrAmbiguousCompany = errors.New("ambiguous company JSON: no block matches the requested company")
+
+// ErrNoCompanyJSON is returned when a page carries no valid company JSON block. For a
+// page loaded with a session cookie, it usually means the cookie expired or was
+// challenged and LinkedIn served a login or guest page instead.
+var ErrNoCompanyJSON = errors.New("no valid company JSON object found in the HTML")
+
 // ExtractCompanyJSON parses the HTML string, finds all <code> tags with an ID
-// starting with "bpr-guid", validates their content, and returns the last valid JSON object.
+// starting with "bpr-guid" and validates their content. Pages often carry several
+// company blocks (affiliates, similar pages), so the block whose company entity
+// matches the given identifier (universal name / slug or numeric ID) is selected,
+// and complementary blocks describing the same entity are merged into it.
+// The matched company is always the first COMPANY entry of the returned 'included' array.
+// The similar pages of the company, which come in a block of their own, are attached to
+// it as its "*similarOrganizations" reference.
 // This function is intended for pages loaded with a valid session cookie.
-func ExtractCompanyJSON(htmlContent string) (map[string]interface{}, error) {
+func ExtractCompanyJSON(htmlContent, identifier string) (map[string]interface{}, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
+	return extractCompanyJSON(doc, identifier)
+}
 
-	var validResults []map[string]interface{}
+func extractCompanyJSON(doc *goquery.Document, identifier string) (map[string]interface{}, error) {
+	var validResults, similarBlocks []map[string]interface{}
 
 	// CSS selector to find all <code> tags where the id attribute starts with "bpr-guid".
 	doc.Find(`code[id^="bpr-guid"]`).Each(func(i int, s *goquery.Selection) {
@@ -70,21 +111,189 @@ func ExtractCompanyJSON(htmlContent string) (map[string]interface{}, error) {
 
 		if isJSONValid(parsedJSON) {
 			validResults = append(validResults, parsedJSON)
+		} else if similarURNs(parsedJSON) != nil {
+			similarBlocks = append(similarBlocks, parsedJSON)
 		}
 	})
 
 	if len(validResults) == 0 {
-		return nil, fmt.Errorf("no valid company JSON object found in the HTML")
+		return nil, ErrNoCompanyJSON
+	}
+
+	log.Printf("✅ Found %d valid JSON objects in the HTML.", len(validResults))
+
+	merged, err := selectCompanyBlock(validResults, identifier)
+	if err != nil {
+		return nil, err
 	}
+	attachSimilarBlocks(merged, similarBlocks)
+	return merged, nil
+}
+
+// selectCompanyBlock picks the blocks describing the requested company and merges them.
+// When nothing matches, even if the page only describes a single company, the page is
+// rejected as ambiguous: that company may be an affiliate or a similar page.
+func selectCompanyBlock(blocks []map[string]interface{}, identifier string) (map[string]interface{}, error) {
+	var matched []map[string]interface{}
+	var targetURN string
+	candidates := map[string]string{}
 
-	if len(validResults) > 1 {
-		log.Printf("Found %d valid JSON objects. Returning the last one. Might change in the future", len(validResults))
+	for _, block := range blocks {
+		for _, company := range companyEntities(block) {
+			urn := utils.SafeGetString(company, "entityUrn")
+			candidates[urn] = utils.SafeGetString(company, "universalName")
+			if targetURN == "" && companyMatches(company, identifier) {
+				targetURN = urn
+			}
+		}
 	}
 
-	log.Printf("✅ Found %d valid JSON objects in the HTML.", len(validResults))
+	if targetURN == "" {
+		names := make([]string, 0, len(candidates))
+		for urn, name := range candidates {
+			names = append(names, fmt.Sprintf("%s (%s)", name, urn))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w %q, candidates: [%s]", ErrAmbiguousCompany, identifier, strings.Join(names, ", "))
+	}
+
+	for _, block := range blocks {
+		for _, company := range companyEntities(block) {
+			if utils.SafeGetString(company, "entityUrn") == targetURN {
+				matched = append(matched, block)
+				break
+			}
+		}
+	}
+
+	if len(matched) > 1 {
+		log.Printf("Merging %d JSON blocks describing %s.", len(matched), targetURN)
+	}
+	return mergeCompanyBlocks(matched, targetURN), nil
+}
+
+// rawCompanyBlocks returns the bpr-guid blocks an extraction was merged from, decoded
+// again from the page so they are untouched: the block itself when there is one, the
+// list of blocks in page order otherwise.
+func rawCompanyBlocks(page *Page, data interface{}) interface{} {
+	merged, _ := data.(map[string]interface{})
+	companies := companyEntities(merged)
+	if len(companies) == 0 {
+		return data
+	}
+	targetURN := utils.SafeGetString(companies[0], "entityUrn")
+
+	var blocks []interface{}
+	page.Doc.Find(`code[id^="bpr-guid"]`).Each(func(i int, s *goquery.Selection) {
+		var block map[string]interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &block); err != nil || !isJSONValid(block) {
+			return
+		}
+		for _, company := range companyEntities(block) {
+			if utils.SafeGetString(company, "entityUrn") == targetURN {
+				blocks = append(blocks, block)
+				return
+			}
+		}
+	})
 
-	// Return the last valid result. @TODO: This might change in the future, better solution needed.
-	return validResults[len(validResults)-1], nil
+	switch len(blocks) {
+	case 0:
+		return data
+	case 1:
+		return blocks[0]
+	}
+	return blocks
+}
+
+// mergeCompanyBlocks combines the blocks describing the same company. The target company
+// entity is merged field by field (first non-null value wins) and placed first in 'included';
+// all other included entities are kept, deduplicated by entityUrn.
+func mergeCompanyBlocks(blocks []map[string]interface{}, targetURN string) map[string]interface{} {
+	company := map[string]interface{}{}
+	var included []interface{}
+	seen := map[string]bool{}
+
+	for _, block := range blocks {
+		items, _ := block["included"].([]interface{})
+		for _, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			urn := utils.SafeGetString(obj, "entityUrn")
+			if urn == targetURN {
+				for key, value := range obj {
+					if existing, ok := company[key]; !ok || existing == nil {
+						company[key] = value
+					}
+				}
+				continue
+			}
+			if urn != "" {
+				if seen[urn] {
+					continue
+				}
+				seen[urn] = true
+			}
+			included = append(included, obj)
+		}
+	}
+
+	return map[string]interface{}{
+		"data":     blocks[0]["data"],
+		"included": append([]interface{}{company}, included...),
+	}
+}
+
+// companyEntities returns the company objects found in a block's 'included' array.
+func companyEntities(block map[string]interface{}) []map[string]interface{} {
+	var companies []map[string]interface{}
+	items, _ := block["included"].([]interface{})
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pageType, _ := obj["pageType"].(string); pageType == "COMPANY" && utils.SafeGetString(obj, "entityUrn") != "" {
+			companies = append(companies, obj)
+		}
+	}
+	return companies
+}
+
+// companyMatches reports whether a company entity is the one identified by a slug or numeric ID.
+func companyMatches(company map[string]interface{}, identifier string) bool {
+	identifier = strings.Trim(strings.TrimSpace(identifier), "/")
+	if identifier == "" {
+		return false
+	}
+	if strings.EqualFold(utils.SafeGetString(company, "universalName"), identifier) {
+		return true
+	}
+	urn := utils.SafeGetString(company, "entityUrn")
+	if urn[strings.LastIndex(urn, ":")+1:] == identifier {
+		return true
+	}
+	profileURL := strings.TrimSuffix(utils.SafeGetString(company, "url"), "/")
+	return strings.HasSuffix(strings.ToLower(profileURL), "/company/"+strings.ToLower(identifier))
+}
+
+// PageCompanySlug returns the slug of the company a saved page describes, read from its
+// canonical or og:url link, or "" when the page does not name one.
+func PageCompanySlug(htmlContent string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return ""
+	}
+	for _, selector := range []string{`link[rel="canonical"]`, `meta[property="og:url"]`} {
+		node := doc.Find(selector).First()
+		link := node.AttrOr("href", node.AttrOr("content", ""))
+		if slug := utils.CompanySlugFromURL(link); slug != "" {
+			return slug
+		}
+	}
+	return ""
 }
 
 // ExtractLdJSONData finds and parses the <script type="application/ld+json"> tag
@@ -94,7 +303,19 @@ func ExtractLdJSONData(htmlContent string) (*LiCompany, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML for ld+json: %w", err)
 	}
+	return extractLdJSONData(doc)
+}
+
+// rawLdJSON returns the parsed ld+json document of the page, or nil.
+func rawLdJSON(page *Page, _ interface{}) interface{} {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(page.Doc.Find("script[type='application/ld+json']").Text()), &raw); err != nil {
+		return nil
+	}
+	return raw
+}
 
+func extractLdJSONData(doc *goquery.Document) (*LiCompany, error) {
 	ldJSONScript := doc.Find("script[type='application/ld+json']")
 	if ldJSONScript.Length() == 0 {
 		return nil, fmt.Errorf("could not find the ld+json script tag in the HTML")
@@ -122,8 +343,15 @@ func ExtractLdJSONData(htmlContent string) (*LiCompany, error) {
 				if slogan, ok := item["slogan"].(string); ok {
 					LiCompany.Slogan = slogan
 				}
-				if sameAs, ok := item["sameAs"]; ok {
-					LiCompany.Website = sameAs
+				// 'url' is usually the company's site, while 'sameAs' mixes the site,
+				// the LinkedIn page and social profiles.
+				links := stringList(item["url"])
+				links = append(links, stringList(item["sameAs"])...)
+				LiCompany.Website, LiCompany.SocialProfiles = classifyLinks(links)
+
+				if ticker, ok := item["tickerSymbol"].(string); ok && ticker != "" {
+					exchange, symbol := utils.ParseTickerSymbol(ticker)
+					LiCompany.Stock = &Stock{Symbol: symbol, Exchange: exchange}
 				}
 
 				// Safely extract nested employee count.
@@ -147,6 +375,11 @@ func ExtractLdJSONData(htmlContent string) (*LiCompany, error) {
 						parts = append(parts, country)
 					}
 					LiCompany.Headquarters = strings.Join(parts, ", ")
+					LiCompany.HeadquartersCountry = strings.ToUpper(country)
+				}
+
+				for _, telephone := range stringList(item["telephone"]) {
+					LiCompany.PhoneNumbers = append(LiCompany.PhoneNumbers, utils.NormalizePhoneNumber(telephone, LiCompany.HeadquartersCountry))
 				}
 
 				return LiCompany, nil
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+// companyBlock returns a valid bpr-guid block describing a single company.
+func companyBlock(universalName, id string) map[string]interface{} {
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"data": map[string]interface{}{
+				"organizationDashCompaniesByUniversalName": map[string]interface{}{},
+			},
+		},
+		"included": []interface{}{
+			map[string]interface{}{
+				"entityUrn":     "urn:li:fsd_company:" + id,
+				"universalName": universalName,
+				"pageType":      "COMPANY",
+			},
+		},
+	}
+}
+
+func TestSelectCompanyBlockMatches(t *testing.T) {
+	blocks := []map[string]interface{}{companyBlock("affiliate", "1"), companyBlock("acme", "2")}
+
+	for _, identifier := range []string{"acme", "ACME", "2"} {
+		merged, err := selectCompanyBlock(blocks, identifier)
+		if err != nil {
+			t.Fatalf("identifier %q: %v", identifier, err)
+		}
+		companies := companyEntities(merged)
+		if len(companies) == 0 || companies[0]["universalName"] != "acme" {
+			t.Errorf("identifier %q: selected %v, want acme", identifier, companies)
+		}
+	}
+}
+
+func TestSelectCompanyBlockSingleNonMatchingEntity(t *testing.T) {
+	blocks := []map[string]interface{}{companyBlock("affiliate", "1")}
+
+	for _, identifier := range []string{"acme", ""} {
+		if _, err := selectCompanyBlock(blocks, identifier); !errors.Is(err, ErrAmbiguousCompany) {
+			t.Errorf("identifier %q: got %v, want ErrAmbiguousCompany", identifier, err)
+		}
+	}
+}
+
+func TestRegisterReplaceReordersByPriority(t *testing.T) {
+	registryMu.Lock()
+	saved := append([]Strategy(nil), registry...)
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+
+	Register(Strategy{Name: "test-a", Version: "v1", Priority: 1000})
+	Register(Strategy{Name: "test-b", Version: "v1", Priority: 1001})
+	Register(Strategy{Name: "test-b", Version: "v1", Priority: 0})
+
+	strategies := Strategies()
+	if strategies[0].Name != "test-b" {
+		t.Errorf("first strategy is %s@%s, want test-b@v1 after its priority was lowered", strategies[0].Name, strategies[0].Version)
+	}
+	count := 0
+	for _, strategy := range strategies {
+		if strategy.Name == "test-b" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("test-b registered %d times, want 1", count)
+	}
+}
+
+func TestGuestRawReturnsGuestMarkup(t *testing.T) {
+	html := `<html><head><script type="application/ld+json">{"@graph":[{"@type":"Organization","name":"Acme"}]}</script></head><body>
+<section class="top-card-layout"><h1 class="top-card-layout__title">Acme</h1></section>
+<dl><dt>Industry</dt><dd>Software</dd></dl>
+</body></html>`
+
+	extraction, err := Extract(html, "acme", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extraction.Strategy != "guest-html" {
+		t.Fatalf("extracted with %s, want guest-html", extraction.Strategy)
+	}
+	raw, ok := extraction.Raw.(map[string]interface{})
+	if !ok {
+		t.Fatalf("raw is %T, want the guest sections", extraction.Raw)
+	}
+	for _, section := range []string{"top_card", "about", "ld+json"} {
+		if raw[section] == nil {
+			t.Errorf("raw lacks the %s section", section)
+		}
+	}
+	if about, _ := raw["about"].(string); about != "<dl><dt>Industry</dt><dd>Software</dd></dl>" {
+		t.Errorf("raw about section = %q", about)
+	}
+}
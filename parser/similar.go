@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// SimilarCompany is an entry of the "Similar pages" / "People also viewed" lists.
+type SimilarCompany struct {
+	Name          string `json:"name"`
+	Slug          string `json:"slug,omitempty"`
+	Industry      string `json:"industry,omitempty"`
+	FollowerCount int    `json:"follower_count,omitempty"`
+	LogoURL       string `json:"logo_url,omitempty"`
+}
+
+// similarOrganizationsField is the reference of a Voyager company entity to the URNs
+// of its similar pages.
+const similarOrganizationsField = "*similarOrganizations"
+
+// ExtractSimilarCompanies returns the similar pages of the requested company, which
+// ExtractCompanyJSON places first in the 'included' array: the company entities its
+// similar-pages collection refers to, in the collection's order. Other companies of the
+// page, such as affiliates, parents and showcase pages, are left out.
+func ExtractSimilarCompanies(jsonData map[string]interface{}) []SimilarCompany {
+	similar := []SimilarCompany{}
+	companies := companyEntities(jsonData)
+	if len(companies) == 0 {
+		return similar
+	}
+	byURN := map[string]map[string]interface{}{}
+	for _, company := range companies[1:] {
+		byURN[utils.SafeGetString(company, "entityUrn")] = company
+	}
+
+	for _, urn := range urnList(companies[0][similarOrganizationsField]) {
+		company, ok := byURN[urn]
+		if !ok {
+			continue
+		}
+		entry := SimilarCompany{
+			Name:     utils.SafeGetString(company, "name"),
+			Slug:     utils.SafeGetString(company, "universalName"),
+			Industry: companyIndustry(company),
+			LogoURL:  vectorImageURL(utils.SafeGet(company, "logo", "image", "vectorImage")),
+		}
+		if count, ok := company["followerCount"].(float64); ok {
+			entry.FollowerCount = int(count)
+		} else if count, ok := utils.SafeGet(company, "followingState", "followerCount").(float64); ok {
+			entry.FollowerCount = int(count)
+		}
+		if entry.Name != "" {
+			similar = append(similar, entry)
+		}
+	}
+	return similar
+}
+
+// similarURNs returns the company URNs of a similar-pages collection block, whose data
+// is keyed by its GraphQL query (e.g. "organizationDashSimilarOrganizationsByCompany"),
+// or nil for other blocks.
+func similarURNs(block map[string]interface{}) []string {
+	data, _ := utils.SafeGet(block, "data", "data").(map[string]interface{})
+	for key, value := range data {
+		if strings.Contains(strings.ToLower(key), "similarorganizations") {
+			if urns := urnList(value); urns != nil {
+				return urns
+			}
+		}
+	}
+	return nil
+}
+
+// attachSimilarBlocks adds the similar pages found in their own blocks to the merged
+// company JSON: their URNs as the company's similar-pages reference, unless it has one,
+// and their entities to 'included'. The page only lists the similar pages of the company
+// it describes, which the merged JSON was selected for.
+func attachSimilarBlocks(merged map[string]interface{}, blocks []map[string]interface{}) {
+	included, _ := merged["included"].([]interface{})
+	if len(blocks) == 0 || len(included) == 0 {
+		return
+	}
+	company, _ := included[0].(map[string]interface{})
+	if company == nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, item := range included {
+		if obj, ok := item.(map[string]interface{}); ok {
+			seen[utils.SafeGetString(obj, "entityUrn")] = true
+		}
+	}
+	var urns []interface{}
+	for _, block := range blocks {
+		for _, urn := range similarURNs(block) {
+			urns = append(urns, urn)
+		}
+		items, _ := block["included"].([]interface{})
+		for _, item := range items {
+			obj, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if urn := utils.SafeGetString(obj, "entityUrn"); urn != "" {
+				if seen[urn] {
+					continue
+				}
+				seen[urn] = true
+			}
+			included = append(included, obj)
+		}
+	}
+	if urnList(company[similarOrganizationsField]) == nil {
+		company[similarOrganizationsField] = urns
+	}
+	merged["included"] = included
+}
+
+// urnList reads a Voyager reference to a list of entities: a list of URNs, or a
+// collection holding them in '*elements'.
+func urnList(value interface{}) []string {
+	if collection, ok := value.(map[string]interface{}); ok {
+		value = collection["*elements"]
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	urns := make([]string, 0, len(items))
+	for _, item := range items {
+		if urn, ok := item.(string); ok && urn != "" {
+			urns = append(urns, urn)
+		}
+	}
+	return urns
+}
+
+// extractGuestSimilarCompanies reads the cards of the "Similar pages" aside of the guest
+// company page. Other asides, e.g. the affiliated pages, are left out.
+func extractGuestSimilarCompanies(doc *goquery.Document) []SimilarCompany {
+	var similar []SimilarCompany
+	seen := map[string]bool{}
+
+	cards := doc.Find(`[data-test-id="similar-pages"] li`)
+	doc.Find("section.aside-section-container").Each(func(i int, section *goquery.Selection) {
+		if _, tagged := section.Attr("data-test-id"); tagged {
+			return
+		}
+		heading := strings.ToLower(cleanText(section.Find("h2, h3").First().Text()))
+		if strings.Contains(heading, "similar pages") || strings.Contains(heading, "people also viewed") {
+			cards = cards.AddSelection(section.Find("li"))
+		}
+	})
+
+	cards.Each(func(i int, card *goquery.Selection) {
+		href, _ := card.Find("a[href]").First().Attr("href")
+		if strings.HasPrefix(href, "/") && !strings.HasPrefix(href, "//") {
+			href = "https://www.linkedin.com" + href
+		}
+		slug := utils.CompanySlugFromURL(href)
+		name := cleanText(card.Find(".base-aside-card__title").First().Text())
+		if name == "" || slug == "" || seen[slug] {
+			return
+		}
+		seen[slug] = true
+
+		entry := SimilarCompany{
+			Name:     name,
+			Slug:     slug,
+			Industry: cleanText(card.Find(".base-aside-card__subtitle").First().Text()),
+		}
+		if match := followersPattern.FindStringSubmatch(card.Text()); match != nil {
+			entry.FollowerCount = parseCount(match[1], match[2])
+		}
+		img := card.Find("img").First()
+		if logo, ok := img.Attr("data-delayed-url"); ok {
+			entry.LogoURL = logo
+		} else if logo, ok := img.Attr("src"); ok && strings.HasPrefix(logo, "http") {
+			entry.LogoURL = logo
+		}
+		similar = append(similar, entry)
+	})
+	return similar
+}
+
+func companyIndustry(company map[string]interface{}) string {
+	if industry := utils.SafeGetString(company, "industry"); industry != "" {
+		return industry
+	}
+	if industry := utils.SafeGetString(company, "industry", "name"); industry != "" {
+		return industry
+	}
+	if industries, ok := company["industries"].([]interface{}); ok && len(industries) > 0 {
+		if first, ok := industries[0].(map[string]interface{}); ok {
+			return utils.SafeGetString(first, "name")
+		}
+	}
+	return ""
+}
+
+// vectorImageURL builds the URL of the largest artifact of a Voyager vectorImage.
+func vectorImageURL(value interface{}) string {
+	image, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	rootURL := utils.SafeGetString(image, "rootUrl")
+	artifacts, _ := image["artifacts"].([]interface{})
+
+	var best string
+	var bestWidth float64
+	for _, artifact := range artifacts {
+		a, ok := artifact.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		width, _ := a["width"].(float64)
+		if segment := utils.SafeGetString(a, "fileIdentifyingUrlPathSegment"); segment != "" && width >= bestWidth {
+			best, bestWidth = segment, width
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return rootURL + best
+}
@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Format identifies the shape of the data produced by a Strategy, so callers know
+// how to post-process it independently of the markup variant it was read from.
+type Format string
+
+const (
+	// FormatVoyagerJSON is normalized Voyager JSON ('data' + 'included'), as embedded
+	// in bpr-guid <code> tags. It is turned into a summary by summarizer.CreateSummary.
+	FormatVoyagerJSON Format = "voyager_json"
+	// FormatCompany is a *LiCompany, ready to be returned as is.
+	FormatCompany Format = "company"
+)
+
+// Page is the input handed to every strategy. The HTML is parsed once and shared.
+type Page struct {
+	HTML       string
+	Doc        *goquery.Document
+	Identifier string
+	// Authenticated is set for pages fetched with a session cookie, on which a
+	// missing company JSON means the session failed.
+	Authenticated bool
+}
+
+// ErrNotApplicable is wrapped by the errors of strategies that do not apply to a page,
+// e.g. a guest strategy on a page without the about section. Extract only moves on to
+// the next strategy after such errors: any other error is final, so that a page of the
+// wrong company or of a failed session is never answered with the data of a fallback.
+var ErrNotApplicable = errors.New("strategy not applicable")
+
+// Strategy is one versioned way of extracting company data from a LinkedIn page.
+// When LinkedIn changes its markup, support for the new layout is added by registering
+// a new strategy (or a new version of an existing one) rather than editing the old one.
+type Strategy struct {
+	Name            string
+	Version         string
+	Priority        int // Lower values are tried first.
+	Format          Format
+	RequiresSession bool
+	// Extract returns an error wrapping ErrNotApplicable when the page lacks the
+	// markup the strategy reads, so the next strategy is tried.
+	Extract func(page *Page) (interface{}, error)
+	// Raw returns the untouched source data behind an extraction. When nil, the
+	// extracted data itself is considered raw.
+	Raw func(page *Page, data interface{}) interface{}
+}
+
+// StrategyInfo identifies the strategy that produced an Extraction.
+type StrategyInfo struct {
+	Strategy string `json:"strategy"`
+	Version  string `json:"version"`
+}
+
+// Extraction is the data produced by the first successful strategy.
+type Extraction struct {
+	StrategyInfo
+	Format Format
+	Data   interface{}
+	Raw    interface{}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Strategy
+)
+
+func init() {
+	Register(Strategy{
+		Name:            "bpr-guid",
+		Version:         "v1",
+		Priority:        10,
+		Format:          FormatVoyagerJSON,
+		RequiresSession: true,
+		Extract: func(page *Page) (interface{}, error) {
+			data, err := extractCompanyJSON(page.Doc, page.Identifier)
+			if errors.Is(err, ErrNoCompanyJSON) && !page.Authenticated {
+				return nil, fmt.Errorf("%w: %w", ErrNotApplicable, err)
+			}
+			return data, err
+		},
+		Raw: rawCompanyBlocks,
+	})
+	Register(Strategy{
+		Name:     "guest-html",
+		Version:  "v1",
+		Priority: 50,
+		Format:   FormatCompany,
+		Extract: func(page *Page) (interface{}, error) {
+			return notApplicable(extractGuestCompanyData(page.Doc))
+		},
+		Raw: rawGuestMarkup,
+	})
+	Register(Strategy{
+		Name:     "ld+json",
+		Version:  "v1",
+		Priority: 100,
+		Format:   FormatCompany,
+		Extract: func(page *Page) (interface{}, error) {
+			return notApplicable(extractLdJSONData(page.Doc))
+		},
+		Raw: rawLdJSON,
+	})
+}
+
+// notApplicable marks the error of a fallback strategy as ErrNotApplicable: the public
+// markup it reads is either there or not, and never describes another company.
+func notApplicable(company *LiCompany, err error) (interface{}, error) {
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotApplicable, err)
+	}
+	return company, nil
+}
+
+// Register adds a strategy to the registry. Registering a strategy with the same
+// name and version as an existing one replaces it, taking its new priority into account.
+func Register(strategy Strategy) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	replaced := false
+	for i, existing := range registry {
+		if existing.Name == strategy.Name && existing.Version == strategy.Version {
+			registry[i] = strategy
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		registry = append(registry, strategy)
+	}
+	sort.SliceStable(registry, func(i, j int) bool {
+		return registry[i].Priority < registry[j].Priority
+	})
+}
+
+// Strategies returns the registered strategies in the order they are tried.
+func Strategies() []Strategy {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Strategy(nil), registry...)
+}
+
+// Extract runs the registered strategies in priority order against the HTML and returns
+// the result of the first one that succeeds. Strategies requiring a session are skipped
+// for unauthenticated pages. A strategy error that does not wrap ErrNotApplicable, such
+// as ErrAmbiguousCompany or ErrNoCompanyJSON on an authenticated page, stops the search.
+// If every strategy is not applicable, the individual errors are joined.
+func Extract(htmlContent, identifier string, authenticated bool) (*Extraction, error) {
+	page, err := newPage(htmlContent, identifier, authenticated)
+	if err != nil {
+		return nil, err
+	}
+	return extract(page, authenticated)
+}
+
+// ExtractSaved is Extract for a saved page, of which it is unknown whether it was
+// loaded with a session: every strategy is tried, and a page without company JSON is
+// handed to the guest strategies.
+func ExtractSaved(htmlContent, identifier string) (*Extraction, error) {
+	page, err := newPage(htmlContent, identifier, false)
+	if err != nil {
+		return nil, err
+	}
+	return extract(page, true)
+}
+
+func extract(page *Page, withSession bool) (*Extraction, error) {
+	var errs []error
+	for _, strategy := range Strategies() {
+		if strategy.RequiresSession && !withSession {
+			continue
+		}
+		data, err := strategy.Extract(page)
+		if err != nil {
+			err = fmt.Errorf("%s@%s: %w", strategy.Name, strategy.Version, err)
+			if !errors.Is(err, ErrNotApplicable) {
+				return nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		log.Printf("✅ Extracted company data with strategy %s@%s.", strategy.Name, strategy.Version)
+		raw := data
+		if strategy.Raw != nil {
+			raw = strategy.Raw(page, data)
+		}
+		return &Extraction{
+			StrategyInfo: StrategyInfo{Strategy: strategy.Name, Version: strategy.Version},
+			Format:       strategy.Format,
+			Data:         data,
+			Raw:          raw,
+		}, nil
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no extraction strategy registered")
+	}
+	return nil, fmt.Errorf("no extraction strategy matched the page: %w", errors.Join(errs...))
+}
+
+func newPage(htmlContent, identifier string, authenticated bool) (*Page, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	return &Page{HTML: htmlContent, Doc: doc, Identifier: identifier, Authenticated: authenticated}, nil
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// SocialProfile is a link to one of the company's profiles on a social network.
+type SocialProfile struct {
+	Network string `json:"network"`
+	URL     string `json:"url"`
+}
+
+var socialNetworks = map[string]string{
+	"twitter.com":    "twitter",
+	"x.com":          "twitter",
+	"facebook.com":   "facebook",
+	"fb.com":         "facebook",
+	"youtube.com":    "youtube",
+	"youtu.be":       "youtube",
+	"instagram.com":  "instagram",
+	"tiktok.com":     "tiktok",
+	"github.com":     "github",
+	"xing.com":       "xing",
+	"pinterest.com":  "pinterest",
+	"medium.com":     "medium",
+	"crunchbase.com": "crunchbase",
+}
+
+// socialNetwork returns the network a normalized URL belongs to, or "".
+func socialNetwork(normalizedURL string) string {
+	u, err := url.Parse(normalizedURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return socialNetworks[host]
+}
+
+// classifyLinks sorts candidate links into the company website (the first link that is
+// neither LinkedIn nor a social network) and the social profiles.
+func classifyLinks(links []string) (string, []SocialProfile) {
+	var website string
+	var profiles []SocialProfile
+	seen := map[string]bool{}
+
+	for _, link := range links {
+		normalized := utils.NormalizeURL(link)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		u, _ := url.Parse(normalized)
+		if utils.IsLinkedInHost(u.Host) {
+			continue
+		}
+		if network := socialNetwork(normalized); network != "" {
+			profiles = append(profiles, SocialProfile{Network: network, URL: normalized})
+			continue
+		}
+		if website == "" {
+			website = normalized
+		}
+	}
+	return website, profiles
+}
+
+// stringList flattens an ld+json value that may be a string or a list of strings.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
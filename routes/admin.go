@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// handleListBreakers reports the state of the circuit breakers.
+// @Summary      Circuit Breakers
+// @Description  Lists the circuit breakers guarding the LinkedIn requests: one per target ('target:company_pages', 'target:voyager_api', 'target:feed_validation') and one per session cookie ('session:' followed by a hash of the cookie), created on first use.
+// @Description  A breaker opens when the failed share of its requests (connection errors, 429, 5xx and 999 responses, redirects to the login page, which only count against the session cookie of authenticated requests) reaches the configured ratio. While open, the requests it guards fail fast with 503. Once 'retry_at' passes, it lets probe requests through and closes when they succeed.
+// @Description  The admin endpoints are disabled unless the server sets ADMIN_TOKEN, and then require it as a bearer token.
+// @Tags         Admin
+// @Produce      json
+// @Param        Authorization  header    string  true  "Bearer followed by the ADMIN_TOKEN of the server"
+// @Success      200  {object}  object{breakers=[]scraper.BreakerState}
+// @Failure      401  {object}  object{error=string}  "Missing or wrong admin token"
+// @Failure      404  {object}  object{error=string}  "The admin endpoints are disabled"
+// @Router       /admin/breakers [get]
+func (r *AppRoutes) handleListBreakers(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"breakers": r.enricher.Breakers()})
+}
+
+// requireAdmin guards the admin endpoints, which reveal the state of the session
+// cookies' breakers: without an admin token they are not served, and with one the
+// requests must send it as a bearer token.
+func (r *AppRoutes) requireAdmin(c *fiber.Ctx) error {
+	if r.adminToken == "" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "The admin endpoints are disabled: set ADMIN_TOKEN to enable them"})
+	}
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(r.adminToken)) != 1 {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing or wrong admin token"})
+	}
+	return c.Next()
+}
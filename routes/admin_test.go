@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{"", "", fiber.StatusNotFound},
+		{"", "Bearer ", fiber.StatusNotFound},
+		{"secret", "", fiber.StatusUnauthorized},
+		{"secret", "Bearer wrong", fiber.StatusUnauthorized},
+		{"secret", "secret", fiber.StatusUnauthorized},
+		{"secret", "Bearer secret", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		routes := &AppRoutes{adminToken: tt.token}
+		app := fiber.New()
+		app.Get("/admin/breakers", routes.requireAdmin, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/admin/breakers", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("token %q, Authorization %q: got status %d, want %d", tt.token, tt.authorization, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/export"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// handleEnrichCSV enriches every row of an uploaded CSV.
+// @Summary      Enrich CSV
+// @Description  Enriches the companies of an uploaded CSV and returns it with the columns li_status ('ok' or 'failed'), li_error, li_slug and the enrichment columns (prefixed with 'li_') appended, in the original row order. 'column' is the header or 0-based index of the column holding the identifiers and 'identifier_type' says what they are. Resolving names and domains searches LinkedIn and requires a session cookie; a domain matches the first of the top search results whose website is on it.
+// @Description  The X-Rows-Enriched and X-Rows-Failed response headers count the rows. At most 200 rows are enriched, with at most one LinkedIn lookup per second (a name takes two lookups, a domain up to four); when the request deadline (X-Request-Timeout, or the server default) passes first, the request fails with 504.
+// @Tags         Company
+// @Accept       multipart/form-data
+// @Produce      text/csv
+// @Param        file                        formData  file    true   "CSV with a header row"
+// @Param        column                      formData  string  true   "Header or 0-based index of the identifier column"
+// @Param        identifier_type             formData  string  true   "What the column holds"  Enums(slug, url, name, domain)
+// @Param        columns                     formData  string  false  "Comma separated enrichment columns (e.g. 'name,website,headquarters.country'); all fields by default"
+// @Param        X-Linkedin-Session-Cookie   header    string  false  "LinkedIn 'li_at' session cookie for authenticated scraping"
+// @Param        X-Proxy-Url                 header    string  false  "Proxy URL to use for scraping"
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                         {string}  string  "The enriched CSV"
+// @Failure      400                         {object}  object{error=string,details=string}
+// @Failure      504                         {object}  object{error=string,details=string}  "The request deadline passed before every row was enriched"
+// @Router       /companies/enrich-csv [post]
+func (r *AppRoutes) handleEnrichCSV(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Form field 'file' must hold the CSV"})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read the uploaded file", "details": err.Error()})
+	}
+	defer file.Close()
+
+	opts := services.CSVEnrichOptions{
+		Column:         c.FormValue("column"),
+		IdentifierType: c.FormValue("identifier_type"),
+		Columns:        export.ParseColumns(c.FormValue("columns")),
+		MaxRows:        services.MaxCSVRows,
+	}
+	if opts.Column == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Form field 'column' cannot be empty"})
+	}
+
+	var buf bytes.Buffer
+	ctx := requestContext(c)
+	report, err := r.enricher.EnrichCSV(ctx, file, &buf, opts)
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		status := fiber.StatusBadRequest
+		if ctx.Err() != nil {
+			status = errorStatus(c, err)
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error":   "Failed to enrich CSV",
+			"details": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, export.MIMECSV+"; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="enriched.csv"`)
+	c.Set("X-Rows-Enriched", strconv.Itoa(report.Enriched))
+	c.Set("X-Rows-Failed", strconv.Itoa(report.Failed))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
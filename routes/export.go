@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/export"
+)
+
+// renderRecords renders a result as JSON, CSV or NDJSON according to the Accept header.
+// For CSV, 'columns' selects and orders the columns and 'max_depth' limits flattening.
+func renderRecords(c *fiber.Ctx, status int, data interface{}) error {
+	return renderExport(c, status, data, data)
+}
+
+// renderExport is renderRecords for responses whose JSON body wraps the records: body is
+// sent as JSON, and the records are exported as CSV or NDJSON, e.g. the company payload
+// of a result rather than its envelope.
+func renderExport(c *fiber.Ctx, status int, body, records interface{}) error {
+	var buf bytes.Buffer
+
+	switch c.Accepts(fiber.MIMEApplicationJSON, export.MIMECSV, export.MIMENDJSON) {
+	case export.MIMECSV:
+		opts := export.Options{
+			Columns:  export.ParseColumns(c.Query("columns")),
+			MaxDepth: c.QueryInt("max_depth"),
+		}
+		if err := export.WriteCSV(&buf, records, opts); err != nil {
+			return exportError(c, err)
+		}
+		c.Set(fiber.HeaderContentType, export.MIMECSV+"; charset=utf-8")
+	case export.MIMENDJSON:
+		if err := export.WriteNDJSON(&buf, records); err != nil {
+			return exportError(c, err)
+		}
+		c.Set(fiber.HeaderContentType, export.MIMENDJSON)
+	default:
+		return c.Status(status).JSON(body)
+	}
+
+	return c.Status(status).Send(buf.Bytes())
+}
+
+func exportError(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Failed to export results",
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/graphqlapi"
+)
+
+// handleGraphQL executes a GraphQL query.
+// @Summary      GraphQL Query
+// @Description  Executes a GraphQL query over companies ('company', 'companies'), their locations, funding and similar companies, and company searches ('searchCompanies'). Every company is fetched once per query, and the companies requested at the same depth, e.g. the similar companies of a company, are enriched together.
+// @Description  The query is sent as a JSON body, or as the 'query' and 'variables' query parameters of a GET request. Field errors are reported in 'errors' next to the data of the fields that succeeded.
+// @Tags         GraphQL
+// @Accept       json
+// @Produce      json
+// @Param        request                    body      graphqlapi.Request  false  "GraphQL request"
+// @Param        query                      query     string              false  "GraphQL query (GET)"
+// @Param        operationName              query     string              false  "Operation to run (GET)"
+// @Param        variables                  query     string              false  "JSON object of variables (GET)"
+// @Param        X-Linkedin-Session-Cookie  header    string              false  "LinkedIn 'li_at' session cookie for authenticated scraping"
+// @Param        X-Proxy-Url                header    string              false  "Proxy URL to use for the LinkedIn requests"
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                        {object}  object{data=object,errors=[]object{message=string,path=[]string}}
+// @Failure      400                        {object}  object{error=string}
+// @Router       /graphql [get]
+// @Router       /graphql [post]
+func (r *AppRoutes) handleGraphQL(c *fiber.Ctx) error {
+	var req graphqlapi.Request
+	if c.Method() == fiber.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'variables' must be a JSON object"})
+			}
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if req.Query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "'query' cannot be empty"})
+	}
+
+	return c.JSON(r.graphql.Execute(requestContext(c), req))
+}
@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// handleParseHTML extracts company data from a saved LinkedIn page.
+// @Summary      Parse Saved Page
+// @Description  Runs a saved LinkedIn company page through the same extraction as the live endpoint, without fetching anything, and returns the company payload with diagnostics about which extraction paths matched. The HTML is sent as the request body, or as the 'file' field of a multipart form.
+// @Description  'slug' names the company the page describes; by default it is read from the page's canonical URL.
+// @Description  With 'Accept: text/csv' or 'Accept: application/x-ndjson' the company payload ('data') is exported as one row.
+// @Tags         Company
+// @Accept       html
+// @Accept       multipart/form-data
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        html    body      string  false  "Saved HTML page"
+// @Param        file    formData  file    false  "Saved HTML page"
+// @Param        slug    query     string  false  "Company slug or ID the page describes"
+// @Param        view    query     string  false  "Payload view"  Enums(summary, detailed, raw)
+// @Param        fields  query     string  false  "Comma separated fields to return, nested with dots (e.g. 'name,website,headquarters.country')"
+// @Param        columns    query  string  false  "CSV only: columns in order, nested with dots"
+// @Param        max_depth  query  int     false  "CSV only: levels of nested objects flattened into columns, 0 for all"
+// @Success      200     {object}  object{scrapeType=string,view=string,parser=object{strategy=string,version=string},data=object,diagnostics=parser.Diagnostics}
+// @Failure      400     {object}  object{error=string}
+// @Failure      422     {object}  object{error=string,details=string,diagnostics=parser.Diagnostics}  "No company data found in the page"
+// @Router       /parse [post]
+func (r *AppRoutes) handleParseHTML(c *fiber.Ctx) error {
+	opts := services.ParseOptions{
+		EnrichOptions: services.EnrichOptions{
+			View:   c.Query("view", services.ViewSummary),
+			Fields: utils.ParseFieldSet(c.Query("fields")),
+		},
+		Slug: c.Query("slug"),
+	}
+	if !services.IsValidView(opts.View) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'view' must be one of summary, detailed, raw"})
+	}
+
+	html, err := readHTML(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request: " + err.Error()})
+	}
+
+	result, err := r.enricher.ParseHTML(requestContext(c), html, opts)
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		body := fiber.Map{
+			"error":   "Failed to parse company page",
+			"details": err.Error(),
+		}
+		var diagErr *services.DiagnosticsError
+		if errors.As(err, &diagErr) {
+			body["diagnostics"] = diagErr.Diagnostics
+		}
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(body)
+	}
+	return renderExport(c, fiber.StatusOK, result, result.Data)
+}
+
+// readHTML returns the uploaded 'file' of a multipart request, or the request body.
+func readHTML(c *fiber.Ctx) (string, error) {
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			return "", errors.New("failed to read the uploaded file")
+		}
+		defer file.Close()
+		raw, err := io.ReadAll(file)
+		if err != nil {
+			return "", errors.New("failed to read the uploaded file")
+		}
+		return string(raw), nil
+	}
+	if len(c.Body()) == 0 {
+		return "", errors.New("the request body must hold the HTML page")
+	}
+	return string(c.Body()), nil
+}
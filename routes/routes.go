@@ -1,68 +1,342 @@
 package routes
 
 import (
+	"context"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/graphqlapi"
+	"github.com/vit0-9/li-enricher-api/history"
 	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/utils"
+	"github.com/vit0-9/li-enricher-api/watch"
 )
 
 type AppRoutes struct {
-	companyService *services.CompanyService
-	authService    *services.AuthService
+	enricher   *enricher.Enricher
+	watchlists watch.Store
+	scheduler  *watch.Scheduler
+	graphql    *graphqlapi.Server
+	adminToken string
 }
 
-func Setup(app *fiber.App) {
-	companyService := services.NewCompanyService()
-	authService := services.NewAuthService()
+// Config holds the long-lived components the routes are served by.
+type Config struct {
+	Enricher   *enricher.Enricher
+	Watchlists watch.Store
+	Scheduler  *watch.Scheduler
+	GraphQL    *graphqlapi.Server
+	// AdminToken is the bearer token of the admin endpoints, which are not served
+	// when it is empty.
+	AdminToken string
+	// DefaultRequestTimeout is the deadline of the requests sent without
+	// X-Request-Timeout, capped by MaxRequestTimeout. Zero means DefaultRequestTimeout.
+	DefaultRequestTimeout time.Duration
+}
+
+func Setup(app *fiber.App, cfg Config) {
 	routes := &AppRoutes{
-		companyService: companyService,
-		authService:    authService,
+		enricher:   cfg.Enricher,
+		watchlists: cfg.Watchlists,
+		scheduler:  cfg.Scheduler,
+		graphql:    cfg.GraphQL,
+		adminToken: cfg.AdminToken,
 	}
 
 	api := app.Group("/api/v1")
+	api.Use(requestTimeout(cfg.DefaultRequestTimeout))
 
 	api.Get("/validate-cookie", routes.handleValidateAuth)
 	api.Get("/companies/:slug", routes.handleScrapeCompany)
-	api.Get("/companies/search/:query", handleSearchCompanies)
+	api.Get("/companies/search/:query", routes.handleSearchCompanies)
+	api.Get("/companies/:slug/similar", routes.handleSimilarCompanies)
+	api.Get("/companies/:slug/history", routes.handleCompanyHistory)
+	api.Get("/companies/:slug/changes", routes.handleCompanyChanges)
+	api.Post("/companies/enrich-csv", routes.handleEnrichCSV)
+	api.Post("/parse", routes.handleParseHTML)
+	api.Get("/graphql", routes.handleGraphQL)
+	api.Post("/graphql", routes.handleGraphQL)
+
+	watchlists := api.Group("/watchlists", routes.requireScheduler)
+	watchlists.Post("", routes.handleCreateWatchlist)
+	watchlists.Get("", routes.handleListWatchlists)
+	watchlists.Get("/:id", routes.handleGetWatchlist)
+	watchlists.Put("/:id", routes.handleUpdateWatchlist)
+	watchlists.Delete("/:id", routes.handleDeleteWatchlist)
+	watchlists.Get("/:id/schedule", routes.handleWatchlistSchedule)
+
+	admin := api.Group("/admin", routes.requireAdmin)
+	admin.Get("/breakers", routes.handleListBreakers)
 }
 
 // handleScrapeCompany scrapes data for a LinkedIn company page.
 // @Summary      Scrape Company Data
-// @Description  Scrapes data for a LinkedIn company page. If a session cookie is provided via the 'X-Linkedin-Session-Cookie' header, it performs a full, authenticated scrape. Otherwise, it performs a public scrape for basic JSON-LD data.
+// @Description  Scrapes data for a LinkedIn company page. If a session cookie is provided via the 'X-Linkedin-Session-Cookie' header, it performs a full, authenticated scrape. Otherwise, it performs a public scrape of the guest page's about section and JSON-LD data.
+// @Description  'view' selects the payload: 'summary' (default), 'detailed' for the resolved company entity or 'raw' for the selected bpr-guid JSON (a list when several blocks describe the company), the guest page sections or the ld+json untouched. 'debug=true' adds diagnostics about the fetched page.
+// @Description  'as_of' returns the stored snapshot valid at that date instead of scraping LinkedIn.
+// @Description  With 'Accept: text/csv' or 'Accept: application/x-ndjson' the company payload ('data') is exported as one row, nested fields flattened into dotted columns.
 // @Tags         Company
 // @Accept       json
 // @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
 // @Param        slug                        path      string                          true   "Company Slug (e.g., 'google')"
+// @Param        view                        query     string                          false  "Payload view"  Enums(summary, detailed, raw)
+// @Param        debug                       query     bool                            false  "Include diagnostics"
+// @Param        fields                      query     string                          false  "Comma separated fields to return, nested with dots (e.g. 'name,website,headquarters.country')"
+// @Param        as_of                       query     string                          false  "Date (2026-07-01) or RFC 3339 time of the snapshot to return"
+// @Param        columns                     query     string                          false  "CSV only: columns in order, nested with dots (e.g. 'name,headquarters.city,funding_summary.last_round.type')"
+// @Param        max_depth                   query     int                             false  "CSV only: levels of nested objects flattened into columns, 0 for all"
 // @Param        X-Linkedin-Session-Cookie   header    string                          false  "LinkedIn 'li_at' session cookie for authenticated scraping"
 // @Param        X-Proxy-Url header string false "Proxy URL to use for validation"
-// @Success      200                         {object}  object{scrapeType=string,data=object}  "Successfully scraped data. 'scrapeType' will be 'full' or 'public'."
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                         {object}  object{scrapeType=string,view=string,parser=object{strategy=string,version=string},data=object,diagnostics=parser.Diagnostics,snapshot=object{id=int,captured_at=string},attempts=int}  "Successfully scraped data. 'scrapeType' will be 'full' or 'public', 'parser' names the extraction strategy and version used, 'attempts' counts the LinkedIn requests, retries included, 'snapshot' identifies the snapshot the result was recorded as or read from."
 // @Failure      400                         {object}  object{error=string}                   "Bad Request - Invalid input"
-// @Failure      500                         {object}  object{error=string,details=string}    "Internal Server Error"
+// @Failure      404                         {object}  object{error=string}                   "No snapshot at the 'as_of' date"
+// @Failure      500                         {object}  object{error=string,details=string,diagnostics=parser.Diagnostics}    "Internal Server Error"
+// @Failure      503                         {object}  object{error=string,details=string}  "A circuit breaker refused the LinkedIn request, see Retry-After"
+// @Failure      504                         {object}  object{error=string,details=string}  "The deadline passed before LinkedIn answered"
 // @Router       /companies/{slug} [get]
 func (r *AppRoutes) handleScrapeCompany(c *fiber.Ctx) error {
 	slug := c.Params("slug")
-	sessionCookie := c.Get("X-Linkedin-Session-Cookie")
-	proxyURL := c.Get("X-Proxy-Url")
+	opts := services.EnrichOptions{
+		View:   c.Query("view", services.ViewSummary),
+		Debug:  c.QueryBool("debug"),
+		Fields: utils.ParseFieldSet(c.Query("fields")),
+	}
 
 	if slug == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Company slug cannot be empty"})
 	}
+	if !services.IsValidView(opts.View) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'view' must be one of summary, detailed, raw"})
+	}
+
+	if asOf := c.Query("as_of"); asOf != "" {
+		at, err := parseAsOf(asOf)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'as_of' must be a date (2006-01-02) or an RFC 3339 time"})
+		}
+		if opts.View != services.ViewSummary {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Snapshots are only available in the summary view"})
+		}
+		return r.renderCompanyAsOf(c, slug, at, opts.Fields)
+	}
 
 	// The handler's only job is to call the service and render the response.
-	data, scrapeType, err := r.companyService.EnrichCompanyData(slug, sessionCookie, proxyURL)
+	result, err := r.enricher.EnrichCompany(requestContext(c), slug, opts)
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		body := fiber.Map{
+			"error":   "Failed to process company data",
+			"details": err.Error(),
+		}
+		var diagErr *services.DiagnosticsError
+		if errors.As(err, &diagErr) {
+			body["diagnostics"] = diagErr.Diagnostics
+		}
+		return c.Status(errorStatus(c, err)).JSON(body)
+	}
+
+	return renderExport(c, fiber.StatusOK, result, result.Data)
+}
+
+func (r *AppRoutes) renderCompanyAsOf(c *fiber.Ctx, slug string, at time.Time, fields utils.FieldSet) error {
+	result, err := r.enricher.CompanyAsOf(requestContext(c), slug, at, fields)
+	if errors.Is(err, history.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "No snapshot of this company at the requested date"})
+	}
+	if errors.Is(err, services.ErrHistoryDisabled) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
 		log.Printf("Error from service: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to read company snapshot",
+			"details": err.Error(),
+		})
+	}
+	return renderExport(c, fiber.StatusOK, result, result.Data)
+}
+
+// requestContext returns the context of a request, carrying the session cookie and
+// proxy sent in the headers.
+func requestContext(c *fiber.Ctx) context.Context {
+	return enricher.ContextWithCredentials(c.UserContext(), c.Get("X-Linkedin-Session-Cookie"), c.Get("X-Proxy-Url"))
+}
+
+// parseAsOf reads an RFC 3339 time, or a date meaning the end of that day (UTC).
+func parseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// handleCompanyHistory lists the stored snapshots of a company.
+// @Summary      Company History
+// @Description  Lists the snapshots stored for a company, newest first, without their data. Every successful enrichment in the summary view is stored as a snapshot, under the company's universal name: a company enriched by numeric ID or by slug has one history, which either identifier lists. The list is paginated: 'next_offset' is set when more snapshots may follow.
+// @Tags         Company
+// @Produce      json
+// @Param        slug    path      string  true   "Company Slug (e.g., 'google')"
+// @Param        limit   query     int     false  "Maximum number of snapshots to return (default 100, at most 1000)"
+// @Param        offset  query     int     false  "Number of snapshots to skip"
+// @Success      200     {object}  object{slug=string,snapshots=[]history.Snapshot,next_offset=int}
+// @Failure      400     {object}  object{error=string}
+// @Failure      404     {object}  object{error=string}
+// @Failure      500     {object}  object{error=string,details=string}
+// @Router       /companies/{slug}/history [get]
+func (r *AppRoutes) handleCompanyHistory(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	if slug == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Company slug cannot be empty"})
+	}
+
+	limit, offset := c.QueryInt("limit"), c.QueryInt("offset")
+	if offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'offset' cannot be negative"})
+	}
+
+	snapshots, err := r.enricher.History(requestContext(c), slug, limit, offset)
+	if errors.Is(err, services.ErrHistoryDisabled) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to list company snapshots",
+			"details": err.Error(),
+		})
+	}
+
+	// Snapshots are kept under the company's universal name, whatever it was requested by.
+	if len(snapshots) > 0 {
+		slug = snapshots[0].Slug
+	}
+	body := fiber.Map{
+		"slug":      history.NormalizeSlug(slug),
+		"snapshots": snapshots,
+	}
+	if len(snapshots) == services.HistoryPageSize(limit) {
+		body["next_offset"] = offset + len(snapshots)
+	}
+	return c.Status(fiber.StatusOK).JSON(body)
+}
+
+// handleCompanyChanges returns the changes between the latest snapshot of a company and a previous one.
+// @Summary      Company Changes
+// @Description  Compares the latest snapshot of a company with a previous one field by field. Lists such as office_locations and specialities are compared by element. By default the previous snapshot of the same scrape type is used; 'from' selects a snapshot by ID and 'since' the snapshot valid at a date, or the one before the latest snapshot when that is the latest. 'refresh=true' scrapes the company first, so the comparison starts from a fresh result.
+// @Tags         Company
+// @Produce      json
+// @Param        slug                        path      string  true   "Company Slug (e.g., 'google')"
+// @Param        from                        query     int     false  "ID of the snapshot to compare with"
+// @Param        since                       query     string  false  "Date (2026-07-01) or RFC 3339 time of the snapshot to compare with"
+// @Param        refresh                     query     bool    false  "Scrape the company before comparing"
+// @Param        X-Linkedin-Session-Cookie   header    string  false  "LinkedIn 'li_at' session cookie, used with refresh"
+// @Param        X-Proxy-Url                 header    string  false  "Proxy URL, used with refresh"
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                         {object}  services.ChangeSet
+// @Failure      400                         {object}  object{error=string}
+// @Failure      404                         {object}  object{error=string}
+// @Failure      500                         {object}  object{error=string,details=string}
+// @Failure      503                         {object}  object{error=string,details=string}  "A circuit breaker refused the LinkedIn request, see Retry-After"
+// @Failure      504                         {object}  object{error=string,details=string}  "The deadline passed before LinkedIn answered"
+// @Router       /companies/{slug}/changes [get]
+func (r *AppRoutes) handleCompanyChanges(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	if slug == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Company slug cannot be empty"})
+	}
+
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		var err error
+		if since, err = parseAsOf(value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter 'since' must be a date (2006-01-02) or an RFC 3339 time"})
+		}
+	}
+
+	if c.QueryBool("refresh") {
+		// The service is called directly, past the cache, so that a new snapshot is taken.
+		_, err := r.enricher.CompanyService().EnrichCompanyData(requestContext(c), slug, c.Get("X-Linkedin-Session-Cookie"), c.Get("X-Proxy-Url"), services.EnrichOptions{})
+		if err != nil {
+			log.Printf("Error from service: %v", err)
+			return c.Status(errorStatus(c, err)).JSON(fiber.Map{
+				"error":   "Failed to process company data",
+				"details": err.Error(),
+			})
+		}
+	}
+
+	changes, err := r.enricher.Changes(requestContext(c), slug, int64(c.QueryInt("from")), since)
+	if errors.Is(err, history.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Not enough snapshots of this company to compare"})
+	}
+	if errors.Is(err, services.ErrHistoryDisabled) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to compare company snapshots",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(changes)
+}
+
+// handleSimilarCompanies returns the companies LinkedIn lists as similar to a company.
+// @Summary      Similar Companies
+// @Description  Returns the "Similar pages" / "People also viewed" companies of a LinkedIn company page, for lookalike prospecting. Works with and without a session cookie.
+// @Description  With 'Accept: text/csv' or 'Accept: application/x-ndjson' the similar companies are exported one per row.
+// @Tags         Company
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        slug                        path      string                          true   "Company Slug (e.g., 'google')"
+// @Param        fields                      query     string                          false  "Comma separated fields of every similar company to return (e.g. 'name,url')"
+// @Param        columns                     query     string                          false  "CSV only: columns in order"
+// @Param        X-Linkedin-Session-Cookie   header    string                          false  "LinkedIn 'li_at' session cookie for authenticated scraping"
+// @Param        X-Proxy-Url header string false "Proxy URL to use for the request"
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                         {object}  object{similar_companies=[]parser.SimilarCompany}
+// @Failure      400                         {object}  object{error=string}
+// @Failure      500                         {object}  object{error=string,details=string}
+// @Failure      503                         {object}  object{error=string,details=string}  "A circuit breaker refused the LinkedIn request, see Retry-After"
+// @Failure      504                         {object}  object{error=string,details=string}  "The deadline passed before LinkedIn answered"
+// @Router       /companies/{slug}/similar [get]
+func (r *AppRoutes) handleSimilarCompanies(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	if slug == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Company slug cannot be empty"})
+	}
+
+	similar, err := r.enricher.SimilarCompanies(requestContext(c), slug)
+	if err != nil {
+		log.Printf("Error from service: %v", err)
+		return c.Status(errorStatus(c, err)).JSON(fiber.Map{
 			"error":   "Failed to process company data",
 			"details": err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"scrapeType": scrapeType,
-		"data":       data,
-	})
+	projected, err := utils.ParseFieldSet(c.Query("fields")).Apply(similar)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to select fields",
+			"details": err.Error(),
+		})
+	}
+
+	return renderExport(c, fiber.StatusOK, fiber.Map{"similar_companies": projected}, projected)
 }
 
 // handleValidateAuth checks if a given LinkedIn session cookie is valid.
@@ -72,9 +346,12 @@ func (r *AppRoutes) handleScrapeCompany(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        X-Linkedin-Session-Cookie   header    string                                 true   "LinkedIn 'li_at' session cookie"
 // @Param        X-Proxy-Url header string false "Proxy URL to use for validation"
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
 // @Success      200                         {object}  object{valid=bool}
 // @Failure      400                         {object}  object{error=string}
 // @Failure      500                         {object}  object{error=string,details=string}
+// @Failure      503                         {object}  object{error=string,details=string}  "A circuit breaker refused the LinkedIn request, see Retry-After"
+// @Failure      504                         {object}  object{error=string,details=string}  "The deadline passed before LinkedIn answered"
 // @Router       /validate-cookie [get]
 func (r *AppRoutes) handleValidateAuth(c *fiber.Ctx) error {
 	sessionCookie := c.Get("X-Linkedin-Session-Cookie")
@@ -82,12 +359,10 @@ func (r *AppRoutes) handleValidateAuth(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Header 'X-Linkedin-Session-Cookie' is required"})
 	}
 
-	proxyURL := c.Get("X-Proxy-Url")
-
-	isValid, err := r.authService.ValidateSession(sessionCookie, proxyURL)
+	isValid, err := r.enricher.ValidateSession(requestContext(c))
 	if err != nil {
 		log.Printf("Error during session validation: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(errorStatus(c, err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"valid": isValid})
@@ -96,16 +371,25 @@ func (r *AppRoutes) handleValidateAuth(c *fiber.Ctx) error {
 // handleSearchCompanies godoc
 // @Summary Search companies on LinkedIn
 // @Description Searches for companies using LinkedIn GraphQL API with the given query string and session cookie.
+// @Description Results are returned as CSV or NDJSON with 'Accept: text/csv' or 'Accept: application/x-ndjson'.
 // @Tags LinkedIn
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param query path string true "Search query"
+// @Param fields query string false "Comma separated fields to return (e.g. 'id,name')"
+// @Param columns query string false "CSV only: columns in order, nested with dots"
+// @Param max_depth query int false "CSV only: levels of nested objects flattened into columns, 0 for all"
 // @Param X-Linkedin-Session-Cookie header string true "LinkedIn session cookie (li_at)"
-// @Success      200                         {object}  object{valid=bool}
+// @Param        X-Request-Timeout           header    string  false  "Deadline of the request: a duration (30s) or seconds, at most 10m, the server default (5m) when omitted"
+// @Success      200                         {array}   services.SearchResult
 // @Failure      400                         {object}  object{error=string}
 // @Failure      500                        {object}  object{error=string,details=string}
+// @Failure      503                         {object}  object{error=string,details=string}  "A circuit breaker refused the LinkedIn request, see Retry-After"
+// @Failure      504                         {object}  object{error=string,details=string}  "The deadline passed before LinkedIn answered"
 // @Router /companies/search/{query} [get]
-func handleSearchCompanies(c *fiber.Ctx) error {
+func (r *AppRoutes) handleSearchCompanies(c *fiber.Ctx) error {
 	searchQuery := c.Params("query")
 	sessionCookie := c.Get("X-Linkedin-Session-Cookie")
 
@@ -116,13 +400,21 @@ func handleSearchCompanies(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "X-Linkedin-Session-Cookie header is required"})
 	}
 
-	results, err := services.SearchCompanies(searchQuery, sessionCookie)
+	results, err := r.enricher.SearchCompanies(requestContext(c), searchQuery)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(errorStatus(c, err)).JSON(fiber.Map{
 			"error":   "Failed to execute search",
 			"details": err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(results)
+	projected, err := utils.ParseFieldSet(c.Query("fields")).Apply(results)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to select fields",
+			"details": err.Error(),
+		})
+	}
+
+	return renderRecords(c, fiber.StatusOK, projected)
 }
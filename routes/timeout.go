@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/scraper"
+)
+
+// MaxRequestTimeout is the longest deadline a client can set with X-Request-Timeout.
+const MaxRequestTimeout = 10 * time.Minute
+
+// DefaultRequestTimeout is the deadline of the requests sent without X-Request-Timeout.
+// The HTTP server does not notice clients that disconnect, so without it an abandoned
+// request would run until its retries are used up.
+const DefaultRequestTimeout = 5 * time.Minute
+
+// requestTimeout sets the deadline of the request context from the X-Request-Timeout
+// header: a duration ("30s", "1m30s") or a number of seconds of at most MaxRequestTimeout,
+// defaultTimeout when the header is missing. The LinkedIn requests still running when it
+// passes are cancelled.
+func requestTimeout(defaultTimeout time.Duration) fiber.Handler {
+	if defaultTimeout <= 0 {
+		defaultTimeout = DefaultRequestTimeout
+	}
+	if defaultTimeout > MaxRequestTimeout {
+		defaultTimeout = MaxRequestTimeout
+	}
+
+	return func(c *fiber.Ctx) error {
+		timeout := defaultTimeout
+		if value := c.Get("X-Request-Timeout"); value != "" {
+			var ok bool
+			if timeout, ok = parseTimeout(value); !ok {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Header 'X-Request-Timeout' must be a positive duration (30s) or number of seconds, at most " + MaxRequestTimeout.String()})
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+// parseTimeout parses a duration or a number of seconds, reporting false unless it is
+// positive and at most MaxRequestTimeout. Numbers are checked before their conversion,
+// which overflows for NaN, infinities and huge values.
+func parseTimeout(value string) (time.Duration, bool) {
+	var timeout time.Duration
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if math.IsNaN(seconds) || seconds <= 0 || seconds > MaxRequestTimeout.Seconds() {
+			return 0, false
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	} else if timeout, err = time.ParseDuration(value); err != nil {
+		return 0, false
+	}
+	return timeout, timeout > 0 && timeout <= MaxRequestTimeout
+}
+
+// errorStatus is the status of a failed service call: 504 when the request deadline
+// passed before LinkedIn answered, 503 with a Retry-After header when a circuit
+// breaker refused the request, 500 otherwise.
+func errorStatus(c *fiber.Ctx, err error) int {
+	var openErr *scraper.CircuitOpenError
+	switch {
+	case errors.Is(err, enricher.ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout
+	case errors.As(err, &openErr):
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(math.Ceil(openErr.RetryIn.Seconds())))))
+		return fiber.StatusServiceUnavailable
+	}
+	return fiber.StatusInternalServerError
+}
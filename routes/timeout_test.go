@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequestTimeoutDefaultsWithoutHeader(t *testing.T) {
+	app := fiber.New()
+	app.Use(requestTimeout(50 * time.Millisecond))
+	app.Get("/", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.SendStatus(fiber.StatusGatewayTimeout)
+		case <-time.After(5 * time.Second):
+			return c.SendStatus(fiber.StatusOK)
+		}
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil), 2000)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusGatewayTimeout {
+		t.Fatalf("got status %d, want the request context cancelled (504)", resp.StatusCode)
+	}
+}
+
+func TestRequestTimeoutHeader(t *testing.T) {
+	app := fiber.New()
+	app.Use(requestTimeout(time.Hour))
+	app.Get("/", func(c *fiber.Ctx) error {
+		deadline, ok := c.UserContext().Deadline()
+		if !ok {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.SendString(time.Until(deadline).Round(time.Second).String())
+	})
+
+	tests := []struct {
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"", fiber.StatusOK, MaxRequestTimeout.String()},
+		{"90", fiber.StatusOK, "1m30s"},
+		{"3m", fiber.StatusOK, "3m0s"},
+		{"600", fiber.StatusOK, "10m0s"},
+		{"10m", fiber.StatusOK, "10m0s"},
+		{"1h", fiber.StatusBadRequest, ""},
+		{"601", fiber.StatusBadRequest, ""},
+		{"1e300", fiber.StatusBadRequest, ""},
+		{"NaN", fiber.StatusBadRequest, ""},
+		{"Inf", fiber.StatusBadRequest, ""},
+		{"-Inf", fiber.StatusBadRequest, ""},
+		{"0", fiber.StatusBadRequest, ""},
+		{"-5", fiber.StatusBadRequest, ""},
+		{"1e-12", fiber.StatusBadRequest, ""},
+		{"-5s", fiber.StatusBadRequest, ""},
+		{"soon", fiber.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.header != "" {
+			req.Header.Set("X-Request-Timeout", tt.header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%q: request failed: %v", tt.header, err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%q: got status %d, want %d", tt.header, resp.StatusCode, tt.wantStatus)
+			continue
+		}
+		if tt.wantStatus != fiber.StatusOK {
+			continue
+		}
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+		if got := string(body[:n]); got != tt.wantBody {
+			t.Errorf("%q: got deadline in %s, want %s", tt.header, got, tt.wantBody)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package routes
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vit0-9/li-enricher-api/watch"
+)
+
+type watchlistRequest struct {
+	Name       string   `json:"name"`
+	Slugs      []string `json:"slugs"`
+	Schedule   string   `json:"schedule" example:"0 6 * * 1"`
+	WebhookURL string   `json:"webhook_url,omitempty"`
+}
+
+// handleCreateWatchlist registers a watchlist.
+// @Summary      Create Watchlist
+// @Description  Registers a list of company slugs re-enriched on a cron schedule (5-field cron expression or descriptors such as '@daily' and '@every 6h'). The session cookie and proxy headers are stored with the watchlist and used for the scheduled scrapes; the cookie is stored encrypted and refused when the server has no cookie key. When a company's fields change, a change event is sent to the configured sinks and to the watchlist's webhook, which must be an http(s) URL of a public address.
+// @Tags         Watchlists
+// @Accept       json
+// @Produce      json
+// @Param        watchlist                   body      watchlistRequest  true   "Watchlist"
+// @Param        X-Linkedin-Session-Cookie   header    string            false  "LinkedIn 'li_at' session cookie for the scheduled scrapes"
+// @Param        X-Proxy-Url                 header    string            false  "Proxy URL for the scheduled scrapes"
+// @Success      201                         {object}  watch.Watchlist
+// @Failure      400                         {object}  object{error=string}
+// @Failure      500                         {object}  object{error=string,details=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists [post]
+func (r *AppRoutes) handleCreateWatchlist(c *fiber.Ctx) error {
+	var body watchlistRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+
+	watchlist := &watch.Watchlist{
+		Name:          body.Name,
+		Slugs:         body.Slugs,
+		Schedule:      body.Schedule,
+		WebhookURL:    body.WebhookURL,
+		SessionCookie: c.Get("X-Linkedin-Session-Cookie"),
+		ProxyURL:      c.Get("X-Proxy-Url"),
+	}
+	if err := watchlist.Normalize(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if watchlist.WebhookURL != "" {
+		if err := watch.ValidateWebhookURL(c.UserContext(), watchlist.WebhookURL); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	if err := r.watchlists.Create(watchlist); err != nil {
+		return watchlistError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(watchlist)
+}
+
+// handleListWatchlists lists the watchlists.
+// @Summary      List Watchlists
+// @Tags         Watchlists
+// @Produce      json
+// @Success      200  {object}  object{watchlists=[]watch.Watchlist}
+// @Failure      500  {object}  object{error=string,details=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists [get]
+func (r *AppRoutes) handleListWatchlists(c *fiber.Ctx) error {
+	watchlists, err := r.watchlists.List()
+	if err != nil {
+		return watchlistError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"watchlists": watchlists})
+}
+
+// handleGetWatchlist returns a watchlist.
+// @Summary      Get Watchlist
+// @Tags         Watchlists
+// @Produce      json
+// @Param        id   path      int  true  "Watchlist ID"
+// @Success      200  {object}  watch.Watchlist
+// @Failure      400  {object}  object{error=string}
+// @Failure      404  {object}  object{error=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists/{id} [get]
+func (r *AppRoutes) handleGetWatchlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid watchlist ID"})
+	}
+	watchlist, err := r.watchlists.Get(int64(id))
+	if err != nil {
+		return watchlistError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(watchlist)
+}
+
+// handleUpdateWatchlist replaces a watchlist.
+// @Summary      Update Watchlist
+// @Description  Replaces the name, slugs, schedule and webhook of a watchlist. The stored session cookie and proxy are replaced when the headers are sent, and kept otherwise.
+// @Tags         Watchlists
+// @Accept       json
+// @Produce      json
+// @Param        id                          path      int               true   "Watchlist ID"
+// @Param        watchlist                   body      watchlistRequest  true   "Watchlist"
+// @Param        X-Linkedin-Session-Cookie   header    string            false  "LinkedIn 'li_at' session cookie for the scheduled scrapes"
+// @Param        X-Proxy-Url                 header    string            false  "Proxy URL for the scheduled scrapes"
+// @Success      200                         {object}  watch.Watchlist
+// @Failure      400                         {object}  object{error=string}
+// @Failure      404                         {object}  object{error=string}
+// @Failure      500                         {object}  object{error=string,details=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists/{id} [put]
+func (r *AppRoutes) handleUpdateWatchlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid watchlist ID"})
+	}
+	var body watchlistRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+
+	watchlist, err := r.watchlists.Get(int64(id))
+	if err != nil {
+		return watchlistError(c, err)
+	}
+	watchlist.Name = body.Name
+	watchlist.Slugs = body.Slugs
+	watchlist.Schedule = body.Schedule
+	watchlist.WebhookURL = body.WebhookURL
+	if cookie := c.Get("X-Linkedin-Session-Cookie"); cookie != "" {
+		watchlist.SessionCookie = cookie
+	}
+	if proxyURL := c.Get("X-Proxy-Url"); proxyURL != "" {
+		watchlist.ProxyURL = proxyURL
+	}
+	if err := watchlist.Normalize(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if watchlist.WebhookURL != "" {
+		if err := watch.ValidateWebhookURL(c.UserContext(), watchlist.WebhookURL); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	if err := r.watchlists.Update(watchlist); err != nil {
+		return watchlistError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(watchlist)
+}
+
+// handleDeleteWatchlist deletes a watchlist.
+// @Summary      Delete Watchlist
+// @Tags         Watchlists
+// @Param        id   path      int  true  "Watchlist ID"
+// @Success      204
+// @Failure      400  {object}  object{error=string}
+// @Failure      404  {object}  object{error=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists/{id} [delete]
+func (r *AppRoutes) handleDeleteWatchlist(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid watchlist ID"})
+	}
+	if err := r.watchlists.Delete(int64(id)); err != nil {
+		return watchlistError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleWatchlistSchedule returns the next scheduled run of every company of a watchlist.
+// @Summary      Watchlist Schedule
+// @Description  Returns, per company of the watchlist, the next scheduled run and the outcome of the last one.
+// @Tags         Watchlists
+// @Produce      json
+// @Param        id   path      int  true  "Watchlist ID"
+// @Success      200  {object}  object{watchlist_id=int,schedule=string,runs=[]watch.RunState}
+// @Failure      400  {object}  object{error=string}
+// @Failure      404  {object}  object{error=string}
+// @Failure      503  {object}  object{error=string}  "Watchlists are disabled"
+// @Router       /watchlists/{id}/schedule [get]
+func (r *AppRoutes) handleWatchlistSchedule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid watchlist ID"})
+	}
+	watchlist, err := r.watchlists.Get(int64(id))
+	if err != nil {
+		return watchlistError(c, err)
+	}
+	runs, err := r.scheduler.Schedule(watchlist)
+	if err != nil {
+		return watchlistError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"watchlist_id": watchlist.ID,
+		"schedule":     watchlist.Schedule,
+		"runs":         runs,
+	})
+}
+
+// requireScheduler refuses the watchlist requests when the server runs without a
+// scheduler, as it does without a snapshot store to find the changes in.
+func (r *AppRoutes) requireScheduler(c *fiber.Ctx) error {
+	if r.scheduler == nil || r.watchlists == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Watchlists are disabled: they need a snapshot store (SNAPSHOT_STORE)",
+		})
+	}
+	return c.Next()
+}
+
+func watchlistError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, watch.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, watch.ErrNoCookieKey):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Session cookies cannot be stored with watchlists on this server",
+			"details": "set WATCHLIST_COOKIE_KEY to store them encrypted, or omit the X-Linkedin-Session-Cookie header",
+		})
+	}
+	log.Printf("Error from watchlist store: %v", err)
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Failed to process watchlist",
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,402 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+// Targets of the LinkedIn requests, each with its own circuit breaker.
+const (
+	TargetCompanyPages   = "company_pages"
+	TargetVoyagerAPI     = "voyager_api"
+	TargetFeedValidation = "feed_validation"
+	TargetOther          = "other"
+)
+
+// Breaker states.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// ErrCircuitOpen is matched by the errors of the requests refused, without being sent,
+// because the breaker of their target or session cookie is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitOpenError is returned for a request refused by an open breaker.
+type CircuitOpenError struct {
+	Breaker string // Name of the breaker, e.g. "target:company_pages".
+	// RetryIn is the time left until the breaker lets probe requests through or, when
+	// its probes are in flight, until their outcome is likely known. It is positive.
+	RetryIn time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker %s is open after repeated LinkedIn failures, retry in %s", e.Breaker, e.RetryIn.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Is(target error) bool { return target == ErrCircuitOpen }
+
+// BreakerPolicy controls when the circuit breakers open. Connection errors, 429, 5xx
+// and 999 responses and redirects to the login page count as failures; other answers,
+// such as 404, as successes. The login redirects of authenticated requests only count
+// against their session cookie.
+type BreakerPolicy struct {
+	// FailureRatio opens a breaker when the failed share of the requests of the
+	// current window reaches it. Zero disables the breakers.
+	FailureRatio float64
+	// MinRequests is the number of requests a window needs before the breaker can open.
+	MinRequests int
+	// Window is the duration after which the counts of a closed breaker start over.
+	Window time.Duration
+	// OpenFor is how long a breaker refuses requests before letting probes through.
+	OpenFor time.Duration
+	// Probes is the number of requests let through at once while half-open, all of
+	// which must succeed to close the breaker. A failed probe opens it again.
+	Probes int
+}
+
+// DefaultBreakerPolicy opens a breaker when half of at least 5 requests within a
+// minute failed, for a minute.
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureRatio: 0.5,
+		MinRequests:  5,
+		Window:       time.Minute,
+		OpenFor:      time.Minute,
+		Probes:       1,
+	}
+}
+
+// BreakerState is a snapshot of a breaker, reported by the admin endpoint.
+type BreakerState struct {
+	Name        string     `json:"name"`
+	State       string     `json:"state"`
+	Requests    int        `json:"requests"`
+	Failures    int        `json:"failures"`
+	OpenedAt    *time.Time `json:"opened_at,omitempty"`
+	RetryAt     *time.Time `json:"retry_at,omitempty"`
+	LastFailure string     `json:"last_failure,omitempty"`
+}
+
+// breaker is the circuit breaker of one target or session cookie.
+type breaker struct {
+	name    string
+	session bool // Guards the requests of a session cookie rather than a target.
+	policy  BreakerPolicy
+
+	mu          sync.Mutex
+	state       string
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	openings    int // Times the breaker opened, which tells the probes of each opening apart.
+	probes      int // Probes in flight while half-open.
+	successes   int // Successful probes while half-open.
+	lastFailure string
+	lastUsed    time.Time
+	users       int // Requests holding the breaker, between forRequest and done.
+}
+
+// reservation is a request admitted by a breaker. probe is set when it was let through
+// as one of the probes of a half-open breaker, after its opening-th opening.
+type reservation struct {
+	breaker *breaker
+	probe   bool
+	opening int
+}
+
+// allow reserves a request, or returns a *CircuitOpenError while the breaker is open
+// or all its probes are in flight.
+func (b *breaker) allow(now time.Time) (reservation, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastUsed = now
+	r := reservation{breaker: b, opening: b.openings}
+	if b.state == BreakerOpen {
+		if wait := b.openedAt.Add(b.policy.OpenFor).Sub(now); wait > 0 {
+			return r, &CircuitOpenError{Breaker: b.name, RetryIn: wait}
+		}
+		b.state, b.probes, b.successes = BreakerHalfOpen, 0, 0
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probes >= max(b.policy.Probes, 1) {
+			// The probes settle the breaker within a request; retrying before is pointless.
+			return r, &CircuitOpenError{Breaker: b.name, RetryIn: b.probeWait()}
+		}
+		b.probes++
+		r.probe = true
+	}
+	return r, nil
+}
+
+// probeWait is how long the requests refused while the probes are in flight should wait:
+// OpenFor, at least a second.
+func (b *breaker) probeWait() time.Duration {
+	return max(b.policy.OpenFor, time.Second)
+}
+
+// endProbe gives back the probe slot of r, if r holds one of the current probes. The
+// caller holds b.mu.
+func (b *breaker) endProbe(r reservation) bool {
+	if !r.probe || r.opening != b.openings || b.state != BreakerHalfOpen || b.probes == 0 {
+		return false
+	}
+	b.probes--
+	return true
+}
+
+// release gives back a reservation whose request was not sent or was cancelled.
+func (b *breaker) release(r reservation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.endProbe(r)
+}
+
+// record counts the outcome of a reserved request. failure is empty for a success.
+// While half-open, only the outcomes of the current probes count.
+func (b *breaker) record(r reservation, now time.Time, failure string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastUsed = now
+	if failure != "" {
+		b.lastFailure = failure
+	}
+	switch b.state {
+	case BreakerHalfOpen:
+		if !b.endProbe(r) {
+			return
+		}
+		if failure != "" {
+			b.open(now)
+			return
+		}
+		if b.successes++; b.successes >= max(b.policy.Probes, 1) {
+			b.state, b.requests, b.failures, b.windowStart = BreakerClosed, 0, 0, now
+		}
+	case BreakerClosed:
+		if now.Sub(b.windowStart) > b.policy.Window {
+			b.requests, b.failures, b.windowStart = 0, 0, now
+		}
+		b.requests++
+		if failure != "" {
+			b.failures++
+		}
+		if b.requests >= b.policy.MinRequests && float64(b.failures) >= b.policy.FailureRatio*float64(b.requests) {
+			b.open(now)
+		}
+	}
+}
+
+func (b *breaker) open(now time.Time) {
+	b.state, b.openedAt, b.probes, b.successes = BreakerOpen, now, 0, 0
+	b.openings++
+}
+
+// idle reports whether the breaker is closed, held by no request and was last used
+// more than after ago, so that dropping it loses nothing but its last failure.
+func (b *breaker) idle(now time.Time, after time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == BreakerClosed && b.users == 0 && now.Sub(b.lastUsed) > after
+}
+
+func (b *breaker) snapshot(now time.Time) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BreakerState{
+		Name:        b.name,
+		State:       b.state,
+		Requests:    b.requests,
+		Failures:    b.failures,
+		LastFailure: b.lastFailure,
+	}
+	if b.state != BreakerClosed {
+		openedAt, retryAt := b.openedAt, b.openedAt.Add(b.policy.OpenFor)
+		state.OpenedAt = &openedAt
+		state.RetryAt = &retryAt
+		if b.state == BreakerOpen && !now.Before(retryAt) {
+			state.State = BreakerHalfOpen
+		}
+	}
+	return state
+}
+
+// breakers holds the breakers of a client, created on first use. Closed breakers idle
+// for two windows are dropped, so that the breakers of cookies no longer used do not
+// pile up.
+type breakers struct {
+	policy BreakerPolicy
+
+	mu        sync.Mutex
+	byName    map[string]*breaker
+	lastSweep time.Time
+}
+
+func newBreakers(policy BreakerPolicy) *breakers {
+	return &breakers{policy: policy, byName: map[string]*breaker{}}
+}
+
+// forRequest returns the breakers a request to rawURL goes through: the one of its
+// target and, for authenticated requests, the one of the session cookie. They are not
+// swept until the request gives them back with done, so that the outcomes of its
+// attempts are not recorded on breakers that were dropped in the meantime.
+func (bs *breakers) forRequest(rawURL, sessionCookie string) []*breaker {
+	if bs.policy.FailureRatio <= 0 {
+		return nil
+	}
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.sweep(time.Now())
+	list := []*breaker{bs.get("target:"+requestTarget(rawURL), false)}
+	if sessionCookie != "" {
+		list = append(list, bs.get("session:"+cookieFingerprint(sessionCookie), true))
+	}
+	return list
+}
+
+// get returns the breaker named name, created if needed, held by one more request.
+// The caller holds bs.mu.
+func (bs *breakers) get(name string, session bool) *breaker {
+	now := time.Now()
+	b, ok := bs.byName[name]
+	if !ok {
+		b = &breaker{name: name, session: session, policy: bs.policy, state: BreakerClosed, windowStart: now}
+		bs.byName[name] = b
+	}
+	b.mu.Lock()
+	b.users++
+	b.lastUsed = now
+	b.mu.Unlock()
+	return b
+}
+
+// done gives back the breakers of a request returned by forRequest.
+func (bs *breakers) done(list []*breaker) {
+	now := time.Now()
+	for _, b := range list {
+		b.mu.Lock()
+		b.users--
+		b.lastUsed = now
+		b.mu.Unlock()
+	}
+}
+
+// sweep drops the idle breakers, at most once per window. The caller holds bs.mu.
+func (bs *breakers) sweep(now time.Time) {
+	idleAfter := 2 * bs.policy.Window
+	if now.Sub(bs.lastSweep) < bs.policy.Window {
+		return
+	}
+	bs.lastSweep = now
+	for name, b := range bs.byName {
+		if b.idle(now, idleAfter) {
+			delete(bs.byName, name)
+		}
+	}
+}
+
+func (bs *breakers) states() []BreakerState {
+	bs.mu.Lock()
+	list := make([]*breaker, 0, len(bs.byName))
+	for _, b := range bs.byName {
+		list = append(list, b)
+	}
+	bs.mu.Unlock()
+
+	now := time.Now()
+	states := make([]BreakerState, 0, len(list))
+	for _, b := range list {
+		states = append(states, b.snapshot(now))
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// allowAll reserves a request on every breaker, or on none when one of them refuses it.
+func allowAll(list []*breaker, now time.Time) ([]reservation, error) {
+	reservations := make([]reservation, 0, len(list))
+	for _, b := range list {
+		r, err := b.allow(now)
+		if err != nil {
+			for _, reserved := range reservations {
+				reserved.breaker.release(reserved)
+			}
+			return nil, err
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations, nil
+}
+
+// requestTarget names the target of a LinkedIn URL.
+func requestTarget(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return TargetOther
+	}
+	switch {
+	case strings.HasPrefix(parsed.Path, "/company/"):
+		return TargetCompanyPages
+	case strings.HasPrefix(parsed.Path, "/voyager/api/"):
+		return TargetVoyagerAPI
+	case strings.HasPrefix(parsed.Path, "/feed"):
+		return TargetFeedValidation
+	}
+	return TargetOther
+}
+
+// cookieFingerprint identifies a session cookie without revealing it.
+func cookieFingerprint(sessionCookie string) string {
+	sum := sha256.Sum256([]byte(sessionCookie))
+	return hex.EncodeToString(sum[:6])
+}
+
+// failureOf returns why the outcome of an attempt counts as a failure, or "" when
+// LinkedIn answered normally. login reports a redirect to the login page, which only
+// says something about the session cookie when the request had one.
+func failureOf(resp *req.Response, err error) (failure string, login bool) {
+	if err != nil {
+		return err.Error(), false
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == StatusBlocked, resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Sprintf("status %d", resp.StatusCode), false
+	}
+	if location := resp.Header.Get("Location"); location != "" && isLoginURL(location) {
+		return "redirected to " + location, true
+	}
+	if resp.Response.Request != nil && resp.Response.Request.URL != nil && isLoginURL(resp.Response.Request.URL.String()) {
+		return "redirected to " + resp.Response.Request.URL.String(), true
+	}
+	return "", false
+}
+
+// isLoginURL reports whether a URL is LinkedIn's login page, auth wall or checkpoint.
+func isLoginURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range []string{"/login", "/authwall", "/uas/login", "/checkpoint"} {
+		if strings.HasPrefix(parsed.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
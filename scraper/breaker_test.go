@@ -0,0 +1,219 @@
+package scraper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSweepKeepsBreakersInUse(t *testing.T) {
+	policy := DefaultBreakerPolicy()
+	bs := newBreakers(policy)
+
+	held := bs.forRequest("https://www.linkedin.com/company/acme", "cookie")
+	idle := bs.forRequest("https://example.com/", "")
+	bs.done(idle)
+
+	later := time.Now().Add(3 * policy.Window)
+	bs.mu.Lock()
+	bs.sweep(later)
+	bs.mu.Unlock()
+
+	for _, b := range held {
+		if bs.byName[b.name] != b {
+			t.Errorf("breaker %s was dropped while a request held it", b.name)
+		}
+	}
+	if _, ok := bs.byName[idle[0].name]; ok {
+		t.Errorf("idle breaker %s was kept", idle[0].name)
+	}
+
+	// The failures recorded by the request land on the breakers still in use.
+	for i := 0; i < policy.MinRequests; i++ {
+		reservations, err := allowAll(held, later)
+		if err != nil {
+			t.Fatalf("request refused: %v", err)
+		}
+		for _, r := range reservations {
+			r.breaker.record(r, later, "status 503")
+		}
+	}
+	bs.done(held)
+	for _, state := range bs.states() {
+		if state.Name == held[0].name && state.State != BreakerOpen {
+			t.Errorf("breaker %s is %s after %d failures, want open", state.Name, state.State, policy.MinRequests)
+		}
+	}
+}
+
+// testBreaker returns a closed breaker of policy.
+func testBreaker(policy BreakerPolicy, now time.Time) *breaker {
+	return &breaker{name: "target:test", policy: policy, state: BreakerClosed, windowStart: now, lastUsed: now}
+}
+
+// fail records count failed requests on b.
+func fail(t *testing.T, b *breaker, now time.Time, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		r, err := b.allow(now)
+		if err != nil {
+			t.Fatalf("request %d refused: %v", i+1, err)
+		}
+		b.record(r, now, "status 503")
+	}
+}
+
+func TestBusyProbesAskForAPositiveWait(t *testing.T) {
+	policy := DefaultBreakerPolicy()
+	now := time.Now()
+	b := testBreaker(policy, now)
+	fail(t, b, now, policy.MinRequests)
+
+	afterOpen := now.Add(policy.OpenFor)
+	if _, err := b.allow(afterOpen); err != nil {
+		t.Fatalf("probe refused: %v", err)
+	}
+	_, err := b.allow(afterOpen)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("request beside the probe got %v, want a *CircuitOpenError", err)
+	}
+	if openErr.RetryIn <= 0 {
+		t.Errorf("RetryIn = %s while the probe is in flight, want a positive wait", openErr.RetryIn)
+	}
+}
+
+func TestBreakerOpensAtTheFailureThreshold(t *testing.T) {
+	policy := DefaultBreakerPolicy()
+	now := time.Now()
+
+	b := testBreaker(policy, now)
+	fail(t, b, now, policy.MinRequests-1)
+	if b.state != BreakerClosed {
+		t.Fatalf("breaker is %s after %d failures, want closed below MinRequests", b.state, policy.MinRequests-1)
+	}
+	fail(t, b, now, 1)
+	if b.state != BreakerOpen {
+		t.Fatalf("breaker is %s after %d failures, want open", b.state, policy.MinRequests)
+	}
+
+	// Failures below the ratio keep it closed.
+	b = testBreaker(policy, now)
+	for i := 0; i < 2*policy.MinRequests; i++ {
+		r, err := b.allow(now)
+		if err != nil {
+			t.Fatalf("request %d refused: %v", i+1, err)
+		}
+		failure := ""
+		if i%3 == 0 {
+			failure = "status 503"
+		}
+		b.record(r, now, failure)
+	}
+	if b.state != BreakerClosed {
+		t.Errorf("breaker is %s with a third of the requests failed, want closed", b.state)
+	}
+}
+
+func TestOpenBreakerLetsAProbeThroughAfterOpenFor(t *testing.T) {
+	policy := DefaultBreakerPolicy()
+	now := time.Now()
+	b := testBreaker(policy, now)
+	fail(t, b, now, policy.MinRequests)
+
+	_, err := b.allow(now.Add(policy.OpenFor / 2))
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("request while open got %v, want a *CircuitOpenError matching ErrCircuitOpen", err)
+	}
+	if openErr.RetryIn != policy.OpenFor/2 {
+		t.Errorf("RetryIn = %s, want %s", openErr.RetryIn, policy.OpenFor/2)
+	}
+
+	r, err := b.allow(now.Add(policy.OpenFor))
+	if err != nil {
+		t.Fatalf("probe refused after OpenFor: %v", err)
+	}
+	if !r.probe || b.state != BreakerHalfOpen {
+		t.Errorf("probe %v with breaker %s, want a probe of a half-open breaker", r.probe, b.state)
+	}
+}
+
+func TestProbeOutcomeSettlesTheBreaker(t *testing.T) {
+	policy := DefaultBreakerPolicy()
+	now := time.Now()
+
+	for _, tt := range []struct {
+		failure   string
+		wantState string
+	}{
+		{"", BreakerClosed},
+		{"status 999", BreakerOpen},
+	} {
+		b := testBreaker(policy, now)
+		fail(t, b, now, policy.MinRequests)
+		probeAt := now.Add(policy.OpenFor)
+		r, err := b.allow(probeAt)
+		if err != nil {
+			t.Fatalf("probe refused: %v", err)
+		}
+		b.record(r, probeAt, tt.failure)
+
+		if b.state != tt.wantState {
+			t.Errorf("probe failure %q: breaker is %s, want %s", tt.failure, b.state, tt.wantState)
+		}
+		switch tt.wantState {
+		case BreakerClosed:
+			if b.requests != 0 || b.failures != 0 {
+				t.Errorf("closed breaker kept %d requests and %d failures, want fresh counts", b.requests, b.failures)
+			}
+			if _, err := b.allow(probeAt); err != nil {
+				t.Errorf("request refused after the probe closed the breaker: %v", err)
+			}
+		case BreakerOpen:
+			if !b.openedAt.Equal(probeAt) {
+				t.Errorf("breaker reopened at %s, want the probe time %s", b.openedAt, probeAt)
+			}
+			if _, err := b.allow(probeAt.Add(time.Second)); !errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("request after the failed probe got %v, want ErrCircuitOpen", err)
+			}
+		}
+	}
+}
+
+func TestSessionBreakersAreKeyedByCookie(t *testing.T) {
+	bs := newBreakers(DefaultBreakerPolicy())
+	url := "https://www.linkedin.com/company/acme"
+
+	guest := bs.forRequest(url, "")
+	first := bs.forRequest(url, "cookie-a")
+	again := bs.forRequest(url, "cookie-a")
+	other := bs.forRequest(url, "cookie-b")
+
+	if len(guest) != 1 || guest[0].name != "target:"+TargetCompanyPages {
+		t.Fatalf("guest request breakers = %v, want the company pages target only", names(guest))
+	}
+	if len(first) != 2 || first[0] != guest[0] {
+		t.Fatalf("authenticated request breakers = %v, want the shared target and a session breaker", names(first))
+	}
+	if again[1] != first[1] {
+		t.Errorf("the same cookie got breakers %s and %s, want one", first[1].name, again[1].name)
+	}
+	if other[1] == first[1] {
+		t.Errorf("two cookies share the breaker %s", first[1].name)
+	}
+	for _, b := range []*breaker{first[1], other[1]} {
+		if !b.session || !strings.HasPrefix(b.name, "session:") || strings.Contains(b.name, "cookie") {
+			t.Errorf("session breaker %q must be named after a hash of the cookie", b.name)
+		}
+	}
+}
+
+func names(list []*breaker) []string {
+	names := make([]string, 0, len(list))
+	for _, b := range list {
+		names = append(names, b.name)
+	}
+	return names
+}
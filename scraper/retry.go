@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+// StatusBlocked is the non-standard status LinkedIn answers with when it blocks a client.
+const StatusBlocked = 999
+
+// RetryPolicy controls how the GET requests to LinkedIn are retried after connection
+// errors and 429, 5xx or 999 responses.
+type RetryPolicy struct {
+	// MaxAttempts caps the attempts of a request, the first one included. 1 or less
+	// disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles with every retry,
+	// up to MaxBackoff, and the actual delay is drawn at random between half of it and
+	// all of it. A Retry-After header replaces it.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxElapsed caps the time spent on a request, delays included. A retry that could
+	// not start in time, e.g. because of a long Retry-After, is not attempted. Zero
+	// leaves only MaxAttempts and the context deadline.
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryPolicy makes up to 3 attempts within 30 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		MaxElapsed:     30 * time.Second,
+	}
+}
+
+// Get sends a GET request to url with client, with the session cookie when set,
+// retrying it according to the retry policy. prepare sets the other headers and
+// cookies of every attempt. It returns the last response or error, with the number of
+// attempts made.
+//
+// Every attempt goes through the circuit breakers of the target of url and of the
+// session cookie, and is not sent while one of them is open: Get then returns an error
+// matching ErrCircuitOpen.
+func (c *Client) Get(ctx context.Context, client *req.Client, url, sessionCookie string, prepare func(r *req.Request)) (*req.Response, int, error) {
+	breakers := c.breakers.forRequest(url, sessionCookie)
+	defer c.breakers.done(breakers)
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		reservations, err := allowAll(breakers, time.Now())
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+		r := client.R().SetContext(ctx)
+		if sessionCookie != "" {
+			r.SetCookies(&http.Cookie{Name: "li_at", Value: sessionCookie})
+		}
+		if prepare != nil {
+			prepare(r)
+		}
+		resp, err := r.Get(url)
+		recordAll(ctx, reservations, resp, err)
+		if attempt >= c.retry.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, attempt, err
+		}
+
+		delay := c.retry.backoff(attempt, resp)
+		if c.retry.MaxElapsed > 0 && time.Since(start)+delay > c.retry.MaxElapsed {
+			return resp, attempt, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, attempt, err
+		}
+		c.logger.Printf("Retrying %s in %s after attempt %d: %s", url, delay.Round(time.Millisecond), attempt, retryReason(resp, err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// recordAll counts the outcome of an attempt on its breakers. Cancelled attempts say
+// nothing about LinkedIn and are not counted. A redirect to the login page of an
+// authenticated request is blamed on the session cookie alone, so that invalid
+// cookies do not open the breaker of the target for everyone.
+func recordAll(ctx context.Context, reservations []reservation, resp *req.Response, err error) {
+	now := time.Now()
+	failure, login := failureOf(resp, err)
+	authenticated := slices.ContainsFunc(reservations, func(r reservation) bool { return r.breaker.session })
+	for _, r := range reservations {
+		b := r.breaker
+		switch {
+		case err != nil && ctx.Err() != nil:
+			b.release(r)
+		case login && authenticated && !b.session:
+			b.record(r, now, "")
+		default:
+			b.record(r, now, failure)
+		}
+	}
+}
+
+// shouldRetry retries connection errors and the responses telling to come back later,
+// but not cancelled requests.
+func shouldRetry(ctx context.Context, resp *req.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == StatusBlocked:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true
+	}
+	return false
+}
+
+func retryReason(resp *req.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "status " + strconv.Itoa(resp.StatusCode)
+}
+
+// backoff returns the delay before the retry following attempt: the Retry-After of the
+// response when it has one, an exponential backoff with jitter otherwise.
+func (p RetryPolicy) backoff(attempt int, resp *req.Response) time.Duration {
+	if resp != nil && resp.Response != nil {
+		if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return delay
+		}
+	}
+
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryAfter reads a Retry-After header: a number of seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
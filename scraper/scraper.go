@@ -1,62 +1,169 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 
 	"github.com/imroc/req/v3"
 )
 
-// FetchHTML fetches the HTML content of a given URL using a session cookie and an optional proxy.
-func FetchHTML(url, sessionCookie, proxyURL string) (string, error) {
-	client := req.C().ImpersonateChrome()
+// Response is a fetched page along with the details needed for diagnostics.
+type Response struct {
+	Body       string
+	FinalURL   string
+	StatusCode int
+	Attempts   int // Requests sent, retries included.
+}
+
+// StatusError is returned when LinkedIn answers with an unsuccessful status code,
+// e.g. 404 for an unknown company or 999 when the request is blocked.
+type StatusError struct {
+	StatusCode int
+	Attempts   int
+}
+
+func (e *StatusError) Error() string {
+	return attemptsMessage(fmt.Sprintf("bad status code: %d", e.StatusCode), e.Attempts)
+}
+
+// ErrTimeout marks the requests that failed because the deadline of their context
+// passed before LinkedIn answered. Such errors also match context.DeadlineExceeded.
+var ErrTimeout = errors.New("LinkedIn request timed out")
+
+// WrapRequestError prefixes the error of a failed request with message and the number
+// of attempts, marking it with ErrTimeout when the request ran out of time.
+func WrapRequestError(message string, attempts int, err error) error {
+	message = attemptsMessage(message, attempts)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w: %w", message, ErrTimeout, err)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func attemptsMessage(message string, attempts int) string {
+	if attempts > 1 {
+		return fmt.Sprintf("%s after %d attempts", message, attempts)
+	}
+	return message
+}
+
+// Client sends the requests to LinkedIn. It is safe for concurrent use.
+type Client struct {
+	base     *req.Client
+	retry    RetryPolicy
+	breakers *breakers
+	logger   *log.Logger
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetryPolicy sets how requests are retried (default DefaultRetryPolicy()).
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// WithBreakerPolicy sets when the circuit breakers open (default DefaultBreakerPolicy()).
+func WithBreakerPolicy(policy BreakerPolicy) Option {
+	return func(c *Client) { c.breakers = newBreakers(policy) }
+}
+
+// WithLogger sets the logger of the retries (default log.Default()).
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) { c.logger = logger }
+}
 
+// NewClient creates a client sending its requests with clones of base, so that the
+// cookies of one session never leak into another. A nil base impersonates Chrome.
+func NewClient(base *req.Client, opts ...Option) *Client {
+	if base == nil {
+		base = req.C().ImpersonateChrome()
+		base.SetUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.0.0 Safari/537.36")
+		base.SetCommonHeader("Accept-Language", "en-US,en;q=0.9")
+	}
+	c := &Client{base: base, retry: DefaultRetryPolicy(), breakers: newBreakers(DefaultBreakerPolicy()), logger: log.Default()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Breakers returns the state of the circuit breakers used so far, sorted by name.
+// The breakers of session cookies are named after a hash of the cookie.
+func (c *Client) Breakers() []BreakerState {
+	return c.breakers.states()
+}
+
+// defaultClient serves the package-level functions.
+var defaultClient = NewClient(nil)
+
+// HTTPClient returns a clone of the base client, with its own cookie jar, sending its
+// requests through proxyURL when set.
+func (c *Client) HTTPClient(proxyURL string) *req.Client {
+	client := c.base.Clone()
 	if proxyURL != "" {
 		client.SetProxyURL(proxyURL)
 	}
+	return client
+}
 
-	client.SetUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.0.0 Safari/537.36")
-	client.SetCommonHeader("Accept-Language", "en-US,en;q=0.9")
-	r := client.R()
-
-	if sessionCookie != "" {
-		cookie := &http.Cookie{
-			Name:  "li_at",
-			Value: sessionCookie,
-		}
-		r.SetCookies(cookie)
+// FetchHTML fetches the HTML content of a given URL using a session cookie and an optional proxy.
+func FetchHTML(url, sessionCookie, proxyURL string) (string, error) {
+	resp, err := Fetch(url, sessionCookie, proxyURL)
+	if err != nil {
+		return "", err
 	}
-	resp, err := r.Get(url)
+	return resp.Body, nil
+}
+
+// Fetch fetches a page like FetchHTML, also reporting the URL reached after redirects.
+func Fetch(url, sessionCookie, proxyURL string) (*Response, error) {
+	return defaultClient.Fetch(context.Background(), url, sessionCookie, proxyURL)
+}
+
+// Fetch fetches a page, also reporting the URL reached after redirects. The request is
+// retried according to the retry policy, refused while its circuit breakers are open
+// and cancelled with ctx.
+func (c *Client) Fetch(ctx context.Context, url, sessionCookie, proxyURL string) (*Response, error) {
+	resp, attempts, err := c.Get(ctx, c.HTTPClient(proxyURL), url, sessionCookie, nil)
 
 	if err != nil {
-		return "", fmt.Errorf("http get request failed: %w", err)
+		return nil, WrapRequestError("http get request failed", attempts, err)
 	}
 
 	if !resp.IsSuccessState() {
-		return "", fmt.Errorf("bad status code: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Attempts: attempts}
 	}
 
-	return resp.String(), nil
+	finalURL := url
+	if resp.Response.Request != nil && resp.Response.Request.URL != nil {
+		finalURL = resp.Response.Request.URL.String()
+	}
+
+	return &Response{
+		Body:       resp.String(),
+		FinalURL:   finalURL,
+		StatusCode: resp.StatusCode,
+		Attempts:   attempts,
+	}, nil
 }
 
 func ValidateSession(sessionCookie, proxyURL string) (bool, error) {
-	client := req.C().ImpersonateChrome()
-	client.SetRedirectPolicy(req.NoRedirectPolicy())
-	client.SetCommonHeader("Accept-Language", "en-US,en;q=0.9")
+	return defaultClient.ValidateSession(context.Background(), sessionCookie, proxyURL)
+}
 
-	if proxyURL != "" {
-		client.SetProxyURL(proxyURL)
-	}
+// ValidateSession reports whether the session cookie opens the feed without being
+// redirected to the login page.
+func (c *Client) ValidateSession(ctx context.Context, sessionCookie, proxyURL string) (bool, error) {
+	client := c.HTTPClient(proxyURL).SetRedirectPolicy(req.NoRedirectPolicy())
 
-	resp, err := client.R().
-		SetCookies(&http.Cookie{
-			Name:  "li_at",
-			Value: sessionCookie,
-		}).
-		Get("https://www.linkedin.com/feed/")
+	resp, attempts, err := c.Get(ctx, client, "https://www.linkedin.com/feed/", sessionCookie, nil)
 
 	if err != nil {
-		return false, fmt.Errorf("request to validation URL failed: %w", err)
+		return false, WrapRequestError("request to validation URL failed", attempts, err)
 	}
 
 	return resp.StatusCode == http.StatusOK, nil
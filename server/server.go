@@ -0,0 +1,263 @@
+// Package server wires the stores, the watchlist scheduler and the HTTP routes together.
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/swagger"
+	"github.com/vit0-9/li-enricher-api/enricher"
+	"github.com/vit0-9/li-enricher-api/graphqlapi"
+	"github.com/vit0-9/li-enricher-api/grpcapi"
+	"github.com/vit0-9/li-enricher-api/history"
+	"github.com/vit0-9/li-enricher-api/routes"
+	"github.com/vit0-9/li-enricher-api/scraper"
+	"github.com/vit0-9/li-enricher-api/services"
+	"github.com/vit0-9/li-enricher-api/watch"
+
+	_ "github.com/vit0-9/li-enricher-api/docs"
+)
+
+// Run serves the API on port until the server fails, configured by the environment.
+func Run(port string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return fmt.Errorf("failed to open the snapshot store: %w", err)
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
+	cookieKey, err := watchlistCookieKey()
+	if err != nil {
+		return err
+	}
+	watchlists, err := openWatchlistStore(cookieKey)
+	if err != nil {
+		return fmt.Errorf("failed to open the watchlist store: %w", err)
+	}
+	defer watchlists.Close()
+
+	e := enricher.New(
+		enricher.WithHistory(store),
+		enricher.WithRetryPolicy(retryPolicy()),
+		enricher.WithBreakerPolicy(breakerPolicy()),
+	)
+	scheduler, err := watch.NewScheduler(watchlists, e.CompanyService(), changeEventSinks(), watchMinInterval())
+	switch {
+	case errors.Is(err, services.ErrHistoryDisabled):
+		log.Println("Watchlists are disabled: SNAPSHOT_STORE is none.")
+	case err != nil:
+		return err
+	default:
+		scheduler.Start()
+		defer scheduler.Stop()
+	}
+
+	graphqlServer, err := graphqlapi.NewServer(e)
+	if err != nil {
+		return err
+	}
+
+	app := fiber.New(fiber.Config{
+		// Saved LinkedIn pages sent to /parse are often larger than the 4 MB default.
+		BodyLimit: 16 * 1024 * 1024,
+	})
+
+	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	routes.Setup(app, routes.Config{
+		Enricher:   e,
+		Watchlists: watchlists,
+		Scheduler:  scheduler,
+		GraphQL:    graphqlServer,
+
+		AdminToken:            os.Getenv("ADMIN_TOKEN"),
+		DefaultRequestTimeout: requestTimeout(),
+	})
+
+	if grpcPort := grpcPort(); grpcPort != "" {
+		listener, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			return fmt.Errorf("failed to listen for gRPC on port %s: %w", grpcPort, err)
+		}
+		grpcServer := grpcapi.NewGRPCServer(e)
+		defer grpcServer.GracefulStop()
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+		log.Println("Starting gRPC server on localhost:" + grpcPort)
+	}
+
+	log.Println("Starting server on http://localhost:" + port)
+	log.Println("API documentation available at http://localhost:" + port + "/swagger/index.html")
+	return app.Listen(":" + port)
+}
+
+// grpcPort is the port of the gRPC API set by GRPC_PORT. The gRPC API has neither
+// authentication nor TLS, so it is only served when the port is set; "none" disables
+// it too.
+func grpcPort() string {
+	if port := os.Getenv("GRPC_PORT"); port != "none" {
+		return port
+	}
+	return ""
+}
+
+// openSnapshotStore creates the store of company snapshots selected by SNAPSHOT_STORE:
+// "sqlite" (default, file set by SNAPSHOT_DB_PATH), "memory" or "none".
+func openSnapshotStore() (history.Store, error) {
+	switch os.Getenv("SNAPSHOT_STORE") {
+	case "", "sqlite":
+		path := os.Getenv("SNAPSHOT_DB_PATH")
+		if path == "" {
+			path = "snapshots.db"
+		}
+		log.Println("Storing company snapshots in " + path)
+		return history.NewSQLiteStore(path)
+	case "memory":
+		return history.NewMemoryStore(), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown SNAPSHOT_STORE %q", os.Getenv("SNAPSHOT_STORE"))
+	}
+}
+
+// openWatchlistStore stores the watchlists next to the snapshots: in the SQLite database
+// when SNAPSHOT_STORE is "sqlite", in memory otherwise. The session cookies stored in
+// the database are encrypted with cookieKey; without one they are refused.
+func openWatchlistStore(cookieKey []byte) (watch.Store, error) {
+	switch os.Getenv("SNAPSHOT_STORE") {
+	case "", "sqlite":
+		path := os.Getenv("SNAPSHOT_DB_PATH")
+		if path == "" {
+			path = "snapshots.db"
+		}
+		var opts []watch.SQLiteOption
+		if cookieKey != nil {
+			opts = append(opts, watch.WithCookieKey(cookieKey))
+		}
+		return watch.NewSQLiteStore(path, opts...)
+	default:
+		return watch.NewMemoryStore(), nil
+	}
+}
+
+// watchlistCookieKey is the key encrypting the session cookies of the stored watchlists,
+// set by WATCHLIST_COOKIE_KEY as 32 base64-encoded bytes (openssl rand -base64 32).
+func watchlistCookieKey() ([]byte, error) {
+	value := os.Getenv("WATCHLIST_COOKIE_KEY")
+	if value == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("WATCHLIST_COOKIE_KEY must be 32 base64-encoded bytes")
+	}
+	return key, nil
+}
+
+// changeEventSinks returns the sinks of watchlist change events configured by
+// WATCH_WEBHOOK_URL and WATCH_EVENTS_FILE (NDJSON).
+func changeEventSinks() []watch.Sink {
+	var sinks []watch.Sink
+	if webhookURL := os.Getenv("WATCH_WEBHOOK_URL"); webhookURL != "" {
+		sinks = append(sinks, watch.NewWebhookSink(webhookURL))
+	}
+	if path := os.Getenv("WATCH_EVENTS_FILE"); path != "" {
+		sinks = append(sinks, watch.NewNDJSONSink(path))
+	}
+	return sinks
+}
+
+// retryPolicy is the default retry policy of the LinkedIn requests, with the attempts
+// and the time spent per request set by RETRY_MAX_ATTEMPTS and RETRY_MAX_ELAPSED.
+func retryPolicy() scraper.RetryPolicy {
+	policy := scraper.DefaultRetryPolicy()
+	if value := os.Getenv("RETRY_MAX_ATTEMPTS"); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err == nil && attempts > 0 {
+			policy.MaxAttempts = attempts
+		} else {
+			log.Printf("Invalid RETRY_MAX_ATTEMPTS %q, using the default", value)
+		}
+	}
+	if value := os.Getenv("RETRY_MAX_ELAPSED"); value != "" {
+		elapsed, err := time.ParseDuration(value)
+		if err == nil {
+			policy.MaxElapsed = elapsed
+		} else {
+			log.Printf("Invalid RETRY_MAX_ELAPSED %q, using the default", value)
+		}
+	}
+	return policy
+}
+
+// breakerPolicy is the default policy of the circuit breakers, with the failure ratio
+// opening them, the requests needed before they can open and how long they stay open
+// set by BREAKER_FAILURE_RATIO (0 disables them), BREAKER_MIN_REQUESTS and
+// BREAKER_OPEN_FOR.
+func breakerPolicy() scraper.BreakerPolicy {
+	policy := scraper.DefaultBreakerPolicy()
+	if value := os.Getenv("BREAKER_FAILURE_RATIO"); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err == nil && ratio >= 0 && ratio <= 1 {
+			policy.FailureRatio = ratio
+		} else {
+			log.Printf("Invalid BREAKER_FAILURE_RATIO %q, using the default", value)
+		}
+	}
+	if value := os.Getenv("BREAKER_MIN_REQUESTS"); value != "" {
+		requests, err := strconv.Atoi(value)
+		if err == nil && requests > 0 {
+			policy.MinRequests = requests
+		} else {
+			log.Printf("Invalid BREAKER_MIN_REQUESTS %q, using the default", value)
+		}
+	}
+	if value := os.Getenv("BREAKER_OPEN_FOR"); value != "" {
+		openFor, err := time.ParseDuration(value)
+		if err == nil && openFor > 0 {
+			policy.OpenFor = openFor
+		} else {
+			log.Printf("Invalid BREAKER_OPEN_FOR %q, using the default", value)
+		}
+	}
+	return policy
+}
+
+// requestTimeout is the deadline of the API requests sent without X-Request-Timeout,
+// set by REQUEST_TIMEOUT (default 5m, at most 10m).
+func requestTimeout() time.Duration {
+	if value := os.Getenv("REQUEST_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err == nil && timeout > 0 {
+			return timeout
+		}
+		log.Printf("Invalid REQUEST_TIMEOUT %q, using the default", value)
+	}
+	return routes.DefaultRequestTimeout
+}
+
+// watchMinInterval is the minimum delay between two scheduled LinkedIn requests,
+// set by WATCH_MIN_INTERVAL (default 15s).
+func watchMinInterval() time.Duration {
+	if value := os.Getenv("WATCH_MIN_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err == nil {
+			return interval
+		}
+		log.Printf("Invalid WATCH_MIN_INTERVAL %q, using the default", value)
+	}
+	return 15 * time.Second
+}
@@ -1,19 +1,22 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vit0-9/li-enricher-api/scraper"
 )
 
-type AuthService struct{}
+type AuthService struct {
+	scraper *scraper.Client
+}
 
-func NewAuthService() *AuthService {
-	return &AuthService{}
+func NewAuthService(deps Dependencies) *AuthService {
+	return &AuthService{scraper: deps.withDefaults().Scraper}
 }
 
-func (s *AuthService) ValidateSession(sessionCookie, proxyURL string) (bool, error) {
-	isValid, err := scraper.ValidateSession(sessionCookie, proxyURL)
+func (s *AuthService) ValidateSession(ctx context.Context, sessionCookie, proxyURL string) (bool, error) {
+	isValid, err := s.scraper.ValidateSession(ctx, sessionCookie, proxyURL)
 	if err != nil {
 		return false, fmt.Errorf("session validation request failed: %w", err)
 	}
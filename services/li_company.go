@@ -0,0 +1,323 @@
+package services
+
+import (
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// Company is the summary of a company, read the same way from full and public scrapes.
+// Fields a scrape does not know, or sections left out by the requested fields, are zero.
+type Company struct {
+	Name           string
+	LinkedinHandle string
+	LinkedinURL    string
+	ExternalID     string
+	Website        string
+	Tagline        string
+	Description    string
+	Industry       string
+	CompanyType    string
+	// CompanySize is the employee count range, e.g. "1001-5000".
+	CompanySize      string
+	FoundedYear      int
+	FollowerCount    int
+	Specialities     []string
+	Stock            *parser.Stock
+	Headquarters     *Location
+	Locations        []Location
+	Funding          *Funding
+	PhoneNumbers     []utils.PhoneNumber
+	SocialProfiles   []parser.SocialProfile
+	SimilarCompanies []parser.SimilarCompany
+}
+
+// Location is an office of a company. Public scrapes only know the headquarters, as a
+// one-line Address and its Country.
+type Location struct {
+	IsHeadquarters bool
+	Line1          string
+	Line2          string
+	City           string
+	State          string
+	Country        string // ISO country code.
+	PostalCode     string
+	Address        string
+	Description    string
+	Latitude       *float64
+	Longitude      *float64
+}
+
+// Funding is the funding of a company, from Crunchbase.
+type Funding struct {
+	TotalRounds          int
+	InvestorCount        int
+	LastRound            *FundingRound
+	Rounds               []FundingRound
+	CrunchbaseProfileURL string
+	CrunchbaseFundingURL string
+	DataLastUpdated      string // RFC 3339.
+}
+
+// FundingRound is a funding round of a company.
+type FundingRound struct {
+	Type                   string
+	TypeCode               string
+	AnnouncedOn            string // 2006-01-02.
+	MoneyRaised            *Money
+	LeadInvestors          []Investor
+	InvestorCount          int
+	CrunchbaseURL          string
+	InvestorsCrunchbaseURL string
+}
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   float64
+	Currency string
+}
+
+// Investor is a lead investor of a funding round.
+type Investor struct {
+	Name          string
+	CrunchbaseURL string
+}
+
+// Company returns the company of a summary result, or nil for the detailed and raw
+// views, whose Data is LinkedIn's JSON. It reads the summary of a full scrape, the
+// company of a public one and their JSON forms, e.g. after a round trip through a cache.
+func (r *CompanyResult) Company() *Company {
+	if r.View != "" && r.View != ViewSummary {
+		return nil
+	}
+	switch data := r.Data.(type) {
+	case *parser.LiCompany:
+		return companyFromPublic(data)
+	case map[string]interface{}:
+		return companyFromMap(data)
+	}
+	return nil
+}
+
+func companyFromPublic(c *parser.LiCompany) *Company {
+	if c == nil {
+		return nil
+	}
+	company := &Company{
+		Name:             c.Name,
+		Website:          c.Website,
+		Tagline:          c.Slogan,
+		Description:      c.Description,
+		Industry:         c.Industry,
+		CompanyType:      c.CompanyType,
+		CompanySize:      c.CompanySize,
+		FoundedYear:      c.FoundedYear,
+		FollowerCount:    c.FollowerCount,
+		Specialities:     c.Specialities,
+		Stock:            c.Stock,
+		PhoneNumbers:     c.PhoneNumbers,
+		SocialProfiles:   c.SocialProfiles,
+		SimilarCompanies: c.SimilarCompanies,
+	}
+	if c.Headquarters != "" || c.HeadquartersCountry != "" {
+		company.Headquarters = &Location{IsHeadquarters: true, Address: c.Headquarters, Country: c.HeadquartersCountry}
+	}
+	return company
+}
+
+// companyFromMap reads a summary map: the summary of a full scrape, with Go values, or
+// the JSON form of a summary of either scrape, with JSON values.
+func companyFromMap(data map[string]interface{}) *Company {
+	company := &Company{
+		Name:           mapString(data, "name"),
+		LinkedinHandle: mapString(data, "linkedin_handle"),
+		LinkedinURL:    mapString(data, "linkedin_profile_url"),
+		ExternalID:     mapString(data, "external_id"),
+		Website:        mapString(data, "website"),
+		Tagline:        mapString(data, "tagline", "slogan"),
+		Description:    mapString(data, "description"),
+		Industry:       mapString(data, "industry"),
+		CompanyType:    mapString(data, "company_type"),
+		CompanySize:    mapString(data, "employee_count_range", "company_size"),
+		FoundedYear:    mapInt(data, "founded_year"),
+		FollowerCount:  mapInt(data, "follower_count"),
+		Specialities:   mapStrings(data, "specialities"),
+	}
+	if stock := mapObject(data, "stock"); stock != nil {
+		company.Stock = &parser.Stock{Symbol: mapString(stock, "symbol"), Exchange: mapString(stock, "exchange")}
+	}
+
+	switch headquarters := data["headquarters"].(type) {
+	case map[string]interface{}:
+		location := locationFromMap(headquarters)
+		company.Headquarters = &location
+	case string:
+		company.Headquarters = &Location{IsHeadquarters: true, Address: headquarters, Country: mapString(data, "headquarters_country")}
+	}
+	for _, location := range mapObjects(data, "office_locations") {
+		company.Locations = append(company.Locations, locationFromMap(location))
+	}
+	if funding := mapObject(data, "funding_summary"); funding != nil {
+		company.Funding = fundingFromMap(funding)
+	}
+
+	switch phones := data["phone_numbers"].(type) {
+	case []utils.PhoneNumber:
+		company.PhoneNumbers = phones
+	case []interface{}:
+		for _, phone := range mapObjects(data, "phone_numbers") {
+			company.PhoneNumbers = append(company.PhoneNumbers, utils.PhoneNumber{
+				Raw:     mapString(phone, "raw"),
+				E164:    mapString(phone, "e164"),
+				Country: mapString(phone, "country"),
+				Valid:   phone["valid"] == true,
+			})
+		}
+	}
+	for _, profile := range mapObjects(data, "social_profiles") {
+		company.SocialProfiles = append(company.SocialProfiles, parser.SocialProfile{
+			Network: mapString(profile, "network"),
+			URL:     mapString(profile, "url"),
+		})
+	}
+	switch similar := data["similar_companies"].(type) {
+	case []parser.SimilarCompany:
+		company.SimilarCompanies = similar
+	case []interface{}:
+		for _, item := range mapObjects(data, "similar_companies") {
+			company.SimilarCompanies = append(company.SimilarCompanies, parser.SimilarCompany{
+				Name:          mapString(item, "name"),
+				Slug:          mapString(item, "slug"),
+				Industry:      mapString(item, "industry"),
+				FollowerCount: mapInt(item, "follower_count"),
+				LogoURL:       mapString(item, "logo_url"),
+			})
+		}
+	}
+	return company
+}
+
+func locationFromMap(data map[string]interface{}) Location {
+	location := Location{
+		IsHeadquarters: data["is_headquarters"] == true,
+		Line1:          mapString(data, "line1"),
+		Line2:          mapString(data, "line2"),
+		City:           mapString(data, "city"),
+		State:          mapString(data, "state"),
+		Country:        mapString(data, "country"),
+		PostalCode:     mapString(data, "postal_code"),
+		Address:        mapString(data, "address"),
+		Description:    mapString(data, "description"),
+	}
+	if latitude, ok := data["latitude"].(float64); ok {
+		location.Latitude = &latitude
+	}
+	if longitude, ok := data["longitude"].(float64); ok {
+		location.Longitude = &longitude
+	}
+	return location
+}
+
+func fundingFromMap(data map[string]interface{}) *Funding {
+	funding := &Funding{
+		TotalRounds:          mapInt(data, "total_rounds"),
+		InvestorCount:        mapInt(data, "investor_count"),
+		CrunchbaseProfileURL: mapString(data, "crunchbase_profile_url"),
+		CrunchbaseFundingURL: mapString(data, "crunchbase_funding_url"),
+		DataLastUpdated:      mapString(data, "data_last_updated_utc"),
+	}
+	if lastRound := mapObject(data, "last_round"); lastRound != nil {
+		round := fundingRoundFromMap(lastRound)
+		funding.LastRound = &round
+	}
+	for _, round := range mapObjects(data, "rounds") {
+		funding.Rounds = append(funding.Rounds, fundingRoundFromMap(round))
+	}
+	return funding
+}
+
+func fundingRoundFromMap(data map[string]interface{}) FundingRound {
+	round := FundingRound{
+		Type:                   mapString(data, "type"),
+		TypeCode:               mapString(data, "type_code"),
+		AnnouncedOn:            mapString(data, "announced_on"),
+		InvestorCount:          mapInt(data, "investor_count"),
+		CrunchbaseURL:          mapString(data, "crunchbase_url"),
+		InvestorsCrunchbaseURL: mapString(data, "investors_crunchbase_url"),
+	}
+	if money := mapObject(data, "money_raised"); money != nil {
+		amount, _ := mapNumber(money["amount"])
+		round.MoneyRaised = &Money{Amount: amount, Currency: mapString(money, "currency")}
+	}
+	for _, investor := range mapObjects(data, "lead_investors") {
+		round.LeadInvestors = append(round.LeadInvestors, Investor{
+			Name:          mapString(investor, "name"),
+			CrunchbaseURL: mapString(investor, "crunchbase_url"),
+		})
+	}
+	return round
+}
+
+// mapString returns the first non-empty string of the keys.
+func mapString(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, _ := data[key].(string); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func mapInt(data map[string]interface{}, key string) int {
+	value, _ := mapNumber(data[key])
+	return int(value)
+}
+
+// mapNumber reads the numbers of summaries (int) and of their JSON form (float64).
+func mapNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func mapStrings(data map[string]interface{}, key string) []string {
+	switch values := data[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		list := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func mapObject(data map[string]interface{}, key string) map[string]interface{} {
+	object, _ := data[key].(map[string]interface{})
+	return object
+}
+
+// mapObjects reads the lists of objects of summaries ([]map[string]interface{}) and of
+// their JSON form ([]interface{}).
+func mapObjects(data map[string]interface{}, key string) []map[string]interface{} {
+	switch values := data[key].(type) {
+	case []map[string]interface{}:
+		return values
+	case []interface{}:
+		list := make([]map[string]interface{}, 0, len(values))
+		for _, value := range values {
+			if object, ok := value.(map[string]interface{}); ok {
+				list = append(list, object)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -1,46 +1,470 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/vit0-9/li-enricher-api/history"
 	"github.com/vit0-9/li-enricher-api/parser"
 	"github.com/vit0-9/li-enricher-api/scraper"
 	"github.com/vit0-9/li-enricher-api/summarizer"
+	"github.com/vit0-9/li-enricher-api/utils"
 )
 
-type CompanyService struct{}
+// Views of the company payload.
+const (
+	ViewSummary  = "summary"  // The summarized company (default).
+	ViewDetailed = "detailed" // The resolved company entity.
+	ViewRaw      = "raw"      // The selected bpr-guid block(s), guest markup or ld+json, untouched.
+)
+
+// IsValidView reports whether view is one of the supported views.
+func IsValidView(view string) bool {
+	return view == ViewSummary || view == ViewDetailed || view == ViewRaw
+}
+
+// ErrHistoryDisabled is returned by history lookups when no snapshot store is configured.
+var ErrHistoryDisabled = errors.New("company history is disabled")
+
+// Dependencies are the collaborators of the services. Zero fields get the defaults: a
+// scraper client impersonating Chrome and the standard logger.
+type Dependencies struct {
+	Scraper *scraper.Client
+	Logger  *log.Logger
+}
+
+func (d Dependencies) withDefaults() Dependencies {
+	if d.Scraper == nil {
+		d.Scraper = scraper.NewClient(nil)
+	}
+	if d.Logger == nil {
+		d.Logger = log.Default()
+	}
+	return d
+}
+
+type CompanyService struct {
+	history history.Store
+	search  *SearchService
+	scraper *scraper.Client
+	logger  *log.Logger
+}
+
+// NewCompanyService creates the service. Successful enrichments are persisted as
+// snapshots in store; a nil store disables the company history.
+func NewCompanyService(store history.Store, deps Dependencies) *CompanyService {
+	deps = deps.withDefaults()
+	return &CompanyService{
+		history: store,
+		search:  NewSearchService(deps),
+		scraper: deps.Scraper,
+		logger:  deps.Logger,
+	}
+}
+
+// EnrichOptions tunes what EnrichCompanyData returns.
+type EnrichOptions struct {
+	View   string         // One of the View constants, ViewSummary when empty.
+	Debug  bool           // Attach diagnostics about the fetched page.
+	Fields utils.FieldSet // Sparse fieldset applied to the data, nil for everything.
+}
 
-func NewCompanyService() *CompanyService {
-	return &CompanyService{}
+// CompanyResult is the outcome of an enrichment. 'Parser' reports which extraction
+// strategy and version produced the data.
+type CompanyResult struct {
+	ScrapeType string              `json:"scrapeType"`
+	View       string              `json:"view"`
+	Parser     parser.StrategyInfo `json:"parser"`
+	// Data is the JSON payload of the view, whose shape depends on the scrape type.
+	// Company reads the summary views as a typed Company.
+	Data        interface{}         `json:"data"`
+	Diagnostics *parser.Diagnostics `json:"diagnostics,omitempty"`
+	Snapshot    *SnapshotInfo       `json:"snapshot,omitempty"`
+	// Attempts is the number of requests sent to LinkedIn, retries included. It is
+	// zero when the result was not scraped for this call.
+	Attempts int `json:"attempts,omitempty"`
+
+	// companySlug is the universal name of the scraped company, see companySlug.
+	companySlug string
+}
+
+// SnapshotInfo identifies the snapshot a result was read from or recorded as.
+type SnapshotInfo struct {
+	ID         int64     `json:"id"`
+	CapturedAt time.Time `json:"captured_at"`
+	ScrapeType string    `json:"scrapeType,omitempty"`
+}
+
+// ChangeSet lists the changes of a company between two snapshots.
+type ChangeSet struct {
+	Slug    string           `json:"slug"`
+	From    SnapshotInfo     `json:"from"`
+	To      SnapshotInfo     `json:"to"`
+	Changes []history.Change `json:"changes"`
 }
 
-func (s *CompanyService) EnrichCompanyData(slug, sessionCookie, proxyURL string) (interface{}, string, error) {
+// DiagnosticsError is returned by EnrichCompanyData in debug mode when the page was
+// fetched but the extraction failed, so the diagnostics can still be reported.
+type DiagnosticsError struct {
+	Err         error
+	Diagnostics *parser.Diagnostics
+}
+
+func (e *DiagnosticsError) Error() string { return e.Err.Error() }
+func (e *DiagnosticsError) Unwrap() error { return e.Err }
+
+func (s *CompanyService) EnrichCompanyData(ctx context.Context, slug, sessionCookie, proxyURL string, opts EnrichOptions) (*CompanyResult, error) {
+	result, err := s.enrichCompanyData(ctx, slug, sessionCookie, proxyURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Only complete summaries are kept, so that snapshots stay comparable.
+	if result.View == ViewSummary && opts.Fields == nil {
+		s.saveSnapshot(slug, result)
+	}
+	return result, nil
+}
+
+// enrichCompanyData is EnrichCompanyData without recording a snapshot, for results that
+// may be discarded.
+func (s *CompanyService) enrichCompanyData(ctx context.Context, slug, sessionCookie, proxyURL string, opts EnrichOptions) (*CompanyResult, error) {
 	url := fmt.Sprintf("https://www.linkedin.com/company/%s", slug)
 
-	htmlContent, err := scraper.FetchHTML(url, sessionCookie, proxyURL)
+	resp, err := s.scraper.Fetch(ctx, url, sessionCookie, proxyURL)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch HTML: %w", err)
+		return nil, fmt.Errorf("failed to fetch HTML: %w", err)
 	}
 
 	if sessionCookie != "" {
-		log.Println("Service: Performing full scrape.")
-		jsonData, err := parser.ExtractCompanyJSON(htmlContent)
-		if err != nil {
-			return nil, "full", fmt.Errorf("failed to parse detailed JSON (is session cookie valid?): %w", err)
+		s.logger.Println("Service: Performing full scrape.")
+	} else {
+		s.logger.Println("Service: Performing public scrape.")
+	}
+
+	var diagnostics *parser.Diagnostics
+	if opts.Debug {
+		diagnostics = parser.Diagnose(resp.Body, slug, sessionCookie != "")
+		diagnostics.FinalURL = resp.FinalURL
+	}
+
+	result, err := s.extract(resp.Body, slug, sessionCookie != "", opts)
+	if err != nil {
+		if diagnostics != nil {
+			return nil, &DiagnosticsError{Err: err, Diagnostics: diagnostics}
+		}
+		return nil, err
+	}
+	result.Diagnostics = diagnostics
+	result.Attempts = resp.Attempts
+	result.companySlug = companySlug(slug, result, resp.FinalURL)
+	return result, nil
+}
+
+// companySlug is the universal name of the company of a scraped result, under which its
+// snapshots are kept whatever identifier it was requested by: the linkedin_handle of a
+// full scrape, else the company slug of the page LinkedIn redirected to, else the
+// requested identifier.
+func companySlug(identifier string, result *CompanyResult, finalURL string) string {
+	if company := result.Company(); company != nil && company.LinkedinHandle != "" {
+		return company.LinkedinHandle
+	}
+	if slug := utils.CompanySlugFromURL(finalURL); slug != "" {
+		return slug
+	}
+	return identifier
+}
+
+// ParseOptions tunes what ParseCompanyHTML returns.
+type ParseOptions struct {
+	EnrichOptions
+	Slug string // Company described by the page, read from its canonical URL when empty.
+}
+
+// ParseCompanyHTML extracts the company payload from a saved LinkedIn page without
+// fetching anything. Every strategy is tried, so logged-in and guest pages are both
+// supported, but a logged-in page of another company is rejected, and the result always carries the diagnostics. No snapshot is recorded.
+func (s *CompanyService) ParseCompanyHTML(htmlContent string, opts ParseOptions) (*CompanyResult, error) {
+	slug := opts.Slug
+	if slug == "" {
+		slug = parser.PageCompanySlug(htmlContent)
+	}
+	diagnostics := parser.Diagnose(htmlContent, slug, true)
+
+	extraction, err := parser.ExtractSaved(htmlContent, slug)
+	if err != nil {
+		return nil, &DiagnosticsError{Err: fmt.Errorf("failed to extract company data: %w", err), Diagnostics: diagnostics}
+	}
+	result, err := buildCompanyResult(extraction, opts.View, opts.Fields)
+	if err != nil {
+		return nil, &DiagnosticsError{Err: err, Diagnostics: diagnostics}
+	}
+	if result.Data, err = opts.Fields.Apply(result.Data); err != nil {
+		return nil, &DiagnosticsError{Err: fmt.Errorf("failed to select fields: %w", err), Diagnostics: diagnostics}
+	}
+	result.Diagnostics = diagnostics
+	return result, nil
+}
+
+// saveSnapshot records the result under the universal name of the company, and the
+// identifier it was requested by as an alias of it, so that the history of a company
+// requested by slug, numeric ID or URL is one.
+func (s *CompanyService) saveSnapshot(identifier string, result *CompanyResult) {
+	if s.history == nil {
+		return
+	}
+	slug := result.companySlug
+	if slug == "" {
+		slug = identifier
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		s.logger.Printf("Failed to encode snapshot of %s: %v", slug, err)
+		return
+	}
+	snapshot := &history.Snapshot{
+		Slug:       slug,
+		CapturedAt: time.Now().UTC(),
+		ScrapeType: result.ScrapeType,
+		Parser:     result.Parser,
+		Data:       data,
+	}
+	if err := s.history.Save(snapshot); err != nil {
+		s.logger.Printf("Failed to save snapshot of %s: %v", slug, err)
+		return
+	}
+	if err := s.history.SaveAlias(identifier, slug); err != nil {
+		s.logger.Printf("Failed to save %s as an alias of %s: %v", identifier, slug, err)
+	}
+	result.Snapshot = &SnapshotInfo{ID: snapshot.ID, CapturedAt: snapshot.CapturedAt, ScrapeType: snapshot.ScrapeType}
+}
+
+// HistorySlug returns the slug the snapshots of a company identifier are kept under:
+// the universal name of the company when it was enriched by that identifier before,
+// else the normalized identifier.
+func (s *CompanyService) HistorySlug(identifier string) (string, error) {
+	if s.history == nil {
+		return "", ErrHistoryDisabled
+	}
+	return s.history.Resolve(identifier)
+}
+
+// HistoryEnabled reports whether enrichments are recorded as snapshots.
+func (s *CompanyService) HistoryEnabled() bool {
+	return s.history != nil
+}
+
+// DefaultHistoryLimit and MaxHistoryLimit bound the page of snapshots History returns.
+const (
+	DefaultHistoryLimit = 100
+	MaxHistoryLimit     = 1000
+)
+
+// HistoryPageSize is the number of snapshots History returns at most for a limit.
+func HistoryPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultHistoryLimit
+	}
+	return min(limit, MaxHistoryLimit)
+}
+
+// History lists a page of the snapshots of a company, newest first, without their data,
+// skipping the first offset ones. The page holds at most HistoryPageSize(limit) snapshots.
+func (s *CompanyService) History(slug string, limit, offset int) ([]history.Snapshot, error) {
+	slug, err := s.HistorySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.history.ListInfo(slug, HistoryPageSize(limit), offset)
+}
+
+// CompanyAsOf returns the company as it was at the given time, read from the latest
+// snapshot captured at or before it. It returns history.ErrNotFound when there is none.
+func (s *CompanyService) CompanyAsOf(slug string, at time.Time, fields utils.FieldSet) (*CompanyResult, error) {
+	slug, err := s.HistorySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.history.AsOf(slug, at)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fields.Apply(snapshot.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select fields: %w", err)
+	}
+	return &CompanyResult{
+		ScrapeType: snapshot.ScrapeType,
+		View:       ViewSummary,
+		Parser:     snapshot.Parser,
+		Data:       data,
+		Snapshot:   &SnapshotInfo{ID: snapshot.ID, CapturedAt: snapshot.CapturedAt, ScrapeType: snapshot.ScrapeType},
+	}, nil
+}
+
+func (s *CompanyService) extract(htmlContent, slug string, authenticated bool, opts EnrichOptions) (*CompanyResult, error) {
+	extraction, err := parser.Extract(htmlContent, slug, authenticated)
+	if errors.Is(err, parser.ErrAmbiguousCompany) {
+		return nil, fmt.Errorf("failed to select company JSON: %w", err)
+	}
+	if err != nil {
+		if authenticated {
+			return nil, fmt.Errorf("failed to extract company data (is session cookie valid?): %w", err)
 		}
+		return nil, fmt.Errorf("failed to extract public company data: %w", err)
+	}
 
-		summary, err := summarizer.CreateSummary(jsonData)
+	result, err := buildCompanyResult(extraction, opts.View, opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data, err = opts.Fields.Apply(result.Data); err != nil {
+		return nil, fmt.Errorf("failed to select fields: %w", err)
+	}
+	return result, nil
+}
+
+// buildCompanyResult turns an extraction into the company payload according to its
+// format and the requested view. Sections not selected by fields are skipped.
+func buildCompanyResult(extraction *parser.Extraction, view string, fields utils.FieldSet) (*CompanyResult, error) {
+	if view == "" {
+		view = ViewSummary
+	}
+	result := &CompanyResult{Parser: extraction.StrategyInfo, View: view}
+
+	switch extraction.Format {
+	case parser.FormatVoyagerJSON:
+		result.ScrapeType = "full"
+		jsonData, _ := extraction.Data.(map[string]interface{})
+		switch view {
+		case ViewRaw:
+			result.Data = extraction.Raw
+			return result, nil
+		case ViewDetailed:
+			// ExtractCompanyJSON places the resolved company entity first.
+			included, _ := jsonData["included"].([]interface{})
+			if len(included) == 0 {
+				return nil, fmt.Errorf("no company entity in the selected JSON")
+			}
+			result.Data = included[0]
+			return result, nil
+		}
+
+		summary, err := summarizer.CreateSummary(jsonData, fields)
 		if err != nil {
-			return nil, "full", fmt.Errorf("failed to summarize data: %w", err)
+			return nil, fmt.Errorf("failed to summarize data: %w", err)
 		}
-		return summary, "full", nil
+		result.Data = summary
+	case parser.FormatCompany:
+		result.ScrapeType = "public"
+		result.Data = extraction.Data
+		if view == ViewRaw {
+			result.Data = extraction.Raw
+		}
+	default:
+		return nil, fmt.Errorf("unsupported extraction format %q from %s@%s", extraction.Format, extraction.Strategy, extraction.Version)
 	}
 
-	log.Println("Service: Performing public scrape.")
-	jsonData, err := parser.ExtractLdJSONData(htmlContent)
+	return result, nil
+}
+
+// Changes compares the latest snapshot of a company with a previous one: the snapshot
+// fromID when set, else the snapshot valid at since when set, else the latest previous
+// snapshot of the same scrape type. When since resolves to the latest snapshot itself,
+// the snapshot before it is used. It returns history.ErrNotFound when there is no
+// snapshot to compare.
+func (s *CompanyService) Changes(slug string, fromID int64, since time.Time) (*ChangeSet, error) {
+	slug, err := s.HistorySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.history.List(slug, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(recent) == 0 {
+		return nil, history.ErrNotFound
+	}
+	latest := recent[0]
+
+	var previous *history.Snapshot
+	switch {
+	case fromID != 0:
+		previous, err = s.history.Get(slug, fromID)
+	case !since.IsZero():
+		previous, err = s.history.AsOf(slug, since)
+		if err == nil && previous.ID == latest.ID {
+			previous, err = s.history.Previous(slug, latest.ID, "")
+		}
+	default:
+		previous, err = s.history.Previous(slug, latest.ID, latest.ScrapeType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return diffSnapshots(slug, previous, &latest)
+}
+
+// ChangesBetween compares two given snapshots of a company. It returns
+// history.ErrNotFound when one of them does not exist.
+func (s *CompanyService) ChangesBetween(slug string, fromID, toID int64) (*ChangeSet, error) {
+	slug, err := s.HistorySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.history.Get(slug, fromID)
 	if err != nil {
-		return nil, "public", fmt.Errorf("failed to extract public ld+json data: %w", err)
+		return nil, err
+	}
+	to, err := s.history.Get(slug, toID)
+	if err != nil {
+		return nil, err
+	}
+	return diffSnapshots(slug, from, to)
+}
+
+func diffSnapshots(slug string, from, to *history.Snapshot) (*ChangeSet, error) {
+	changes, err := history.Diff(from.Data, to.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare snapshots %d and %d: %w", from.ID, to.ID, err)
+	}
+	return &ChangeSet{
+		Slug:    history.NormalizeSlug(slug),
+		From:    SnapshotInfo{ID: from.ID, CapturedAt: from.CapturedAt, ScrapeType: from.ScrapeType},
+		To:      SnapshotInfo{ID: to.ID, CapturedAt: to.CapturedAt, ScrapeType: to.ScrapeType},
+		Changes: changes,
+	}, nil
+}
+
+// SimilarCompanies returns the "Similar pages" of a company, read from the same page
+// as the enrichment.
+func (s *CompanyService) SimilarCompanies(ctx context.Context, slug, sessionCookie, proxyURL string) ([]parser.SimilarCompany, error) {
+	result, err := s.EnrichCompanyData(ctx, slug, sessionCookie, proxyURL, EnrichOptions{
+		Fields: utils.FieldSet{"similar_companies": nil},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The projected data is generic JSON whatever the scrape type, decode the list back.
+	var payload struct {
+		SimilarCompanies []parser.SimilarCompany `json:"similar_companies"`
+	}
+	raw, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode similar companies: %w", err)
+	}
+	if payload.SimilarCompanies == nil {
+		return []parser.SimilarCompany{}, nil
 	}
-	return jsonData, "public", nil
+	return payload.SimilarCompanies, nil
 }
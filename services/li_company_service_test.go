@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/history"
+	"github.com/vit0-9/li-enricher-api/scraper"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// newTestService returns a service whose LinkedIn requests are all answered by handler.
+func newTestService(t *testing.T, store history.Store, handler http.Handler) *CompanyService {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	base := req.C().EnableInsecureSkipVerify().SetDial(func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	})
+	return NewCompanyService(store, Dependencies{
+		Scraper: scraper.NewClient(base),
+		Logger:  log.New(io.Discard, "", 0),
+	})
+}
+
+// companyPage is a logged-in company page carrying the bpr-guid block of one company,
+// with the extra attributes set on its entity.
+func companyPage(universalName, id string, extra ...map[string]interface{}) string {
+	company := map[string]interface{}{
+		"entityUrn":     "urn:li:fsd_company:" + id,
+		"universalName": universalName,
+		"name":          "Acme",
+		"pageType":      "COMPANY",
+	}
+	for _, attributes := range extra {
+		for key, value := range attributes {
+			company[key] = value
+		}
+	}
+	block := map[string]interface{}{
+		"data": map[string]interface{}{
+			"data": map[string]interface{}{"organizationDashCompaniesByUniversalName": map[string]interface{}{}},
+		},
+		"included": []interface{}{company},
+	}
+	raw, _ := json.Marshal(block)
+	return fmt.Sprintf(`<html><body><code id="bpr-guid-1">%s</code></body></html>`, html.EscapeString(string(raw)))
+}
+
+func TestSnapshotsOfAnIDAndASlugShareOneHistory(t *testing.T) {
+	store := history.NewMemoryStore()
+	service := newTestService(t, store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, companyPage("acme", "1234"))
+	}))
+
+	for _, identifier := range []string{"1234", "Acme"} {
+		result, err := service.EnrichCompanyData(context.Background(), identifier, "cookie", "", EnrichOptions{})
+		if err != nil {
+			t.Fatalf("enriching %s: %v", identifier, err)
+		}
+		if result.Snapshot == nil {
+			t.Fatalf("enriching %s recorded no snapshot", identifier)
+		}
+	}
+
+	for _, identifier := range []string{"acme", "1234", "ACME"} {
+		snapshots, err := service.History(identifier, 0, 0)
+		if err != nil {
+			t.Fatalf("history of %s: %v", identifier, err)
+		}
+		if len(snapshots) != 2 {
+			t.Fatalf("history of %s holds %d snapshots, want 2", identifier, len(snapshots))
+		}
+		for _, snapshot := range snapshots {
+			if snapshot.Slug != "acme" {
+				t.Errorf("history of %s: snapshot %d kept under %q, want acme", identifier, snapshot.ID, snapshot.Slug)
+			}
+		}
+	}
+
+	changes, err := service.Changes("1234", 0, time.Time{})
+	if err != nil {
+		t.Fatalf("changes of 1234: %v", err)
+	}
+	if changes.Slug != "acme" {
+		t.Errorf("changes of 1234 reported for %q, want acme", changes.Slug)
+	}
+}
+
+func TestSnapshotsFollowTheGuestRedirect(t *testing.T) {
+	store := history.NewMemoryStore()
+	service := newTestService(t, store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/company/1234" {
+			http.Redirect(w, r, "/company/acme/", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, `<html><body><section class="top-card-layout"><h1 class="top-card-layout__title">Acme</h1></section><dl><dt>Industry</dt><dd>Software</dd></dl></body></html>`)
+	}))
+
+	result, err := service.EnrichCompanyData(context.Background(), "1234", "", "", EnrichOptions{})
+	if err != nil {
+		t.Fatalf("enriching 1234: %v", err)
+	}
+	if result.Snapshot == nil {
+		t.Fatal("enriching 1234 recorded no snapshot")
+	}
+	snapshots, err := service.History("acme", 0, 0)
+	if err != nil {
+		t.Fatalf("history of acme: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("history of acme holds %d snapshots, want the one of 1234", len(snapshots))
+	}
+}
+
+func TestEnrichSelectsTheRequestedFields(t *testing.T) {
+	service := newTestService(t, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, companyPage("acme", "1234", map[string]interface{}{
+			"websiteUrl":  "https://acme.example/",
+			"headquarter": map[string]interface{}{"address": map[string]interface{}{"city": "Phoenix", "country": "us"}},
+		}))
+	}))
+
+	tests := []struct {
+		fields string
+		want   map[string]interface{}
+	}{
+		{"name", map[string]interface{}{"name": "Acme"}},
+		{"name,headquarters.country", map[string]interface{}{
+			"name":         "Acme",
+			"headquarters": map[string]interface{}{"country": "US"},
+		}},
+		{"headquarters.city,headquarters.nope", map[string]interface{}{
+			"headquarters": map[string]interface{}{"city": "Phoenix"},
+		}},
+		{"nope", map[string]interface{}{}},
+		{"name,nope.deeper", map[string]interface{}{"name": "Acme"}},
+	}
+	for _, tt := range tests {
+		result, err := service.EnrichCompanyData(context.Background(), "acme", "cookie", "", EnrichOptions{Fields: utils.ParseFieldSet(tt.fields)})
+		if err != nil {
+			t.Fatalf("fields %q: %v", tt.fields, err)
+		}
+		if !reflect.DeepEqual(result.Data, tt.want) {
+			t.Errorf("fields %q: data = %#v, want %#v", tt.fields, result.Data, tt.want)
+		}
+	}
+
+	// An empty selection returns the whole summary.
+	result, err := service.EnrichCompanyData(context.Background(), "acme", "cookie", "", EnrichOptions{Fields: utils.ParseFieldSet("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := result.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("data is %T, want the summary map", result.Data)
+	}
+	for _, field := range []string{"name", "website", "headquarters", "office_locations", "phone_numbers", "similar_companies"} {
+		if _, ok := data[field]; !ok {
+			t.Errorf("the summary without fields is missing %s", field)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/export"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// Kinds of identifiers a CSV column can hold.
+const (
+	IdentifierSlug   = "slug"
+	IdentifierURL    = "url"
+	IdentifierName   = "name"
+	IdentifierDomain = "domain"
+)
+
+// MaxCSVRows is the maximum number of data rows of a CSV uploaded to the API. The rows
+// are enriched while the request waits, so larger lists belong in the batch CLI.
+const MaxCSVRows = 200
+
+// DefaultCSVRowInterval is the minimum delay between two LinkedIn lookups of a CSV.
+const DefaultCSVRowInterval = time.Second
+
+// utf8BOM starts the CSVs saved by spreadsheet programs.
+const utf8BOM = "\ufeff"
+
+// Columns appended to every row of an enriched CSV, before the enrichment columns.
+const (
+	csvColumnStatus = "li_status"
+	csvColumnError  = "li_error"
+	csvColumnSlug   = "li_slug"
+	csvColumnPrefix = "li_"
+)
+
+// domainCandidates is the number of search results checked when resolving a domain.
+const domainCandidates = 3
+
+// IsValidIdentifierType reports whether kind is one of the Identifier constants.
+func IsValidIdentifierType(kind string) bool {
+	switch kind {
+	case IdentifierSlug, IdentifierURL, IdentifierName, IdentifierDomain:
+		return true
+	}
+	return false
+}
+
+// CSVEnrichOptions describes an uploaded CSV and how to enrich it.
+type CSVEnrichOptions struct {
+	// Column is the header (case-insensitive) or 0-based index of the identifier column.
+	Column         string
+	IdentifierType string
+	// Columns selects the enrichment columns as dotted paths of the company data; when
+	// empty, every field found is appended in the export order (see export.SortColumns).
+	Columns       []string
+	SessionCookie string
+	ProxyURL      string
+	// RowInterval is the minimum delay between two LinkedIn lookups (a search or a
+	// company page), DefaultCSVRowInterval when zero. A row resolving a name or a domain
+	// makes several lookups.
+	RowInterval time.Duration
+	// MaxRows refuses CSVs with more data rows. Zero means no limit.
+	MaxRows int
+}
+
+// CSVEnrichReport summarizes an enriched CSV.
+type CSVEnrichReport struct {
+	Rows     int
+	Enriched int
+	Failed   int
+}
+
+type csvRowResult struct {
+	slug  string
+	cells map[string]string
+	err   error
+}
+
+// Pacer spaces LinkedIn lookups, such as those of a CSV or of a batch of companies, by
+// a minimum interval. It is not safe for concurrent use.
+type Pacer struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// NewPacer creates a pacer spacing lookups by interval, DefaultCSVRowInterval when zero.
+func NewPacer(interval time.Duration) *Pacer {
+	if interval <= 0 {
+		interval = DefaultCSVRowInterval
+	}
+	return &Pacer{interval: interval}
+}
+
+// Wait waits until the next lookup may start, or returns the error of ctx.
+func (p *Pacer) Wait(ctx context.Context) error {
+	if err := waitUntil(ctx, p.last.Add(p.interval)); err != nil {
+		return err
+	}
+	p.last = time.Now()
+	return nil
+}
+
+// EnrichCSV enriches every row of a CSV whose first line is a header, and writes the
+// original rows, in order, with the status, the resolved slug and the enrichment
+// columns appended. Rows that cannot be enriched are kept with their error.
+func (s *CompanyService) EnrichCSV(ctx context.Context, in io.Reader, out io.Writer, opts CSVEnrichOptions) (*CSVEnrichReport, error) {
+	if !IsValidIdentifierType(opts.IdentifierType) {
+		return nil, fmt.Errorf("identifier type must be one of slug, url, name, domain")
+	}
+	if (opts.IdentifierType == IdentifierName || opts.IdentifierType == IdentifierDomain) && opts.SessionCookie == "" {
+		return nil, fmt.Errorf("a session cookie is required to resolve company %ss", opts.IdentifierType)
+	}
+
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV is empty")
+	}
+	header, rows := records[0], records[1:]
+	if opts.MaxRows > 0 && len(rows) > opts.MaxRows {
+		return nil, fmt.Errorf("CSV has %d rows, the maximum is %d", len(rows), opts.MaxRows)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], utf8BOM)
+	}
+	// Rows longer than the header keep their extra cells, under empty headers.
+	width := len(header)
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	for len(header) < width {
+		header = append(header, "")
+	}
+	pacer := NewPacer(opts.RowInterval)
+
+	column, err := findCSVColumn(header, opts.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CSVEnrichReport{Rows: len(rows)}
+	results := make([]csvRowResult, len(rows))
+	cache := map[string]csvRowResult{}
+	for i, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("CSV enrichment stopped at row %d of %d: %w", i+1, len(rows), err)
+		}
+		identifier := ""
+		if column < len(row) {
+			identifier = strings.TrimSpace(row[column])
+		}
+		key := strings.ToLower(identifier)
+		result, ok := cache[key]
+		if !ok {
+			result = s.enrichCSVRow(ctx, pacer, identifier, opts)
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("CSV enrichment stopped at row %d of %d: %w", i+1, len(rows), err)
+			}
+			cache[key] = result
+		}
+		if result.err != nil {
+			report.Failed++
+			s.logger.Printf("CSV row %d (%q): %v", i+2, identifier, result.err)
+		} else {
+			report.Enriched++
+		}
+		results[i] = result
+	}
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = enrichmentColumns(results)
+	}
+
+	writer := csv.NewWriter(out)
+	outHeader := append(append([]string(nil), header...), csvColumnStatus, csvColumnError, csvColumnSlug)
+	for _, c := range columns {
+		outHeader = append(outHeader, csvColumnPrefix+c)
+	}
+	if err := writer.Write(outHeader); err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		line := make([]string, width, len(outHeader))
+		copy(line, row)
+		result := results[i]
+		if result.err != nil {
+			line = append(line, "failed", export.SafeCell(result.err.Error()), export.SafeCell(result.slug))
+		} else {
+			line = append(line, "ok", "", export.SafeCell(result.slug))
+		}
+		// The scraped cells end up in spreadsheets; the uploaded ones are kept as they are.
+		for _, c := range columns {
+			line = append(line, export.SafeCell(result.cells[c]))
+		}
+		if err := writer.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return report, writer.Error()
+}
+
+func (s *CompanyService) enrichCSVRow(ctx context.Context, pacer *Pacer, identifier string, opts CSVEnrichOptions) csvRowResult {
+	if identifier == "" {
+		return csvRowResult{err: errors.New("empty identifier")}
+	}
+
+	slug, result, err := s.resolveIdentifier(ctx, pacer, identifier, opts)
+	if err != nil {
+		return csvRowResult{slug: slug, err: err}
+	}
+	if result == nil {
+		if err := pacer.Wait(ctx); err != nil {
+			return csvRowResult{slug: slug, err: err}
+		}
+		result, err = s.EnrichCompanyData(ctx, slug, opts.SessionCookie, opts.ProxyURL, EnrichOptions{})
+		if err != nil {
+			return csvRowResult{slug: slug, err: err}
+		}
+	}
+
+	// Names and domains resolve to numeric IDs; report the company's universal name.
+	if company := result.Company(); company != nil && company.LinkedinHandle != "" {
+		slug = company.LinkedinHandle
+	}
+
+	records, err := export.Records(result.Data)
+	if err != nil {
+		return csvRowResult{slug: slug, err: fmt.Errorf("failed to flatten company data: %w", err)}
+	}
+	if len(records) == 0 {
+		return csvRowResult{slug: slug, err: errors.New("the enrichment returned no company data")}
+	}
+	cells := map[string]string{}
+	export.Flatten(records[0], 0, cells)
+	return csvRowResult{slug: slug, cells: cells}
+}
+
+// resolveIdentifier turns a CSV identifier into a company slug or ID. Resolving a domain
+// enriches the candidates, so the matching result is returned along with the slug. Every
+// LinkedIn lookup waits for the pacer.
+func (s *CompanyService) resolveIdentifier(ctx context.Context, pacer *Pacer, identifier string, opts CSVEnrichOptions) (string, *CompanyResult, error) {
+	switch opts.IdentifierType {
+	case IdentifierSlug:
+		return strings.Trim(identifier, "/"), nil, nil
+	case IdentifierURL:
+		slug := utils.CompanySlugFromURL(utils.NormalizeURL(identifier))
+		if slug == "" {
+			return "", nil, fmt.Errorf("not a LinkedIn company URL: %s", identifier)
+		}
+		return slug, nil, nil
+	case IdentifierName:
+		if err := pacer.Wait(ctx); err != nil {
+			return "", nil, err
+		}
+		results, err := s.search.SearchCompanies(ctx, identifier, opts.SessionCookie, opts.ProxyURL)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(results) == 0 {
+			return "", nil, fmt.Errorf("no company found for name %q", identifier)
+		}
+		return results[0].ID, nil, nil
+	case IdentifierDomain:
+		return s.resolveDomain(ctx, pacer, identifier, opts)
+	}
+	return "", nil, fmt.Errorf("unsupported identifier type %q", opts.IdentifierType)
+}
+
+// resolveDomain searches companies by the domain's name and keeps the first candidate
+// whose website is on that domain. Only the snapshot of that candidate is recorded.
+func (s *CompanyService) resolveDomain(ctx context.Context, pacer *Pacer, domain string, opts CSVEnrichOptions) (string, *CompanyResult, error) {
+	host := websiteHost(domain)
+	if host == "" {
+		return "", nil, fmt.Errorf("invalid domain %q", domain)
+	}
+	query := strings.Split(host, ".")[0]
+
+	if err := pacer.Wait(ctx); err != nil {
+		return "", nil, err
+	}
+	candidates, err := s.search.SearchCompanies(ctx, query, opts.SessionCookie, opts.ProxyURL)
+	if err != nil {
+		return "", nil, err
+	}
+	for i, candidate := range candidates {
+		if i == domainCandidates {
+			break
+		}
+		if err := pacer.Wait(ctx); err != nil {
+			return "", nil, err
+		}
+		result, err := s.enrichCompanyData(ctx, candidate.ID, opts.SessionCookie, opts.ProxyURL, EnrichOptions{})
+		if err != nil {
+			s.logger.Printf("Failed to enrich candidate %s for domain %s: %v", candidate.ID, host, err)
+			continue
+		}
+		if company := result.Company(); company != nil && websiteHost(company.Website) == host {
+			s.saveSnapshot(candidate.ID, result)
+			return candidate.ID, result, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no company with website on %s found", host)
+}
+
+// waitUntil waits until t, or returns the error of ctx when it is done first.
+func waitUntil(ctx context.Context, t time.Time) error {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// websiteHost returns the host of a URL or domain without its "www." prefix.
+func websiteHost(value string) string {
+	normalized := utils.NormalizeURL(value)
+	if normalized == "" {
+		return ""
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// findCSVColumn finds a column by header (case-insensitive) or by 0-based index.
+func findCSVColumn(header []string, column string) (int, error) {
+	column = strings.TrimSpace(column)
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	if index, err := strconv.Atoi(column); err == nil && index >= 0 && index < len(header) {
+		return index, nil
+	}
+	return 0, fmt.Errorf("column %q not found in the CSV header", column)
+}
+
+// enrichmentColumns returns every column found in the results, in the export order.
+func enrichmentColumns(results []csvRowResult) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, result := range results {
+		for column := range result.cells {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	export.SortColumns(columns)
+	return columns
+}
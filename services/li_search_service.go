@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/scraper"
 	"github.com/vit0-9/li-enricher-api/utils"
 )
 
@@ -17,16 +19,32 @@ type SearchResult struct {
 	Text string `json:"text"`
 }
 
+// SearchService searches companies with LinkedIn's typeahead API.
+type SearchService struct {
+	scraper *scraper.Client
+	logger  *log.Logger
+}
+
+func NewSearchService(deps Dependencies) *SearchService {
+	deps = deps.withDefaults()
+	return &SearchService{scraper: deps.Scraper, logger: deps.Logger}
+}
+
+// SearchCompanies searches companies with a default SearchService.
 func SearchCompanies(query, sessionCookie string) ([]SearchResult, error) {
-	client := req.C().ImpersonateChrome()
-	client.EnableDebugLog()
+	return NewSearchService(Dependencies{}).SearchCompanies(context.Background(), query, sessionCookie, "")
+}
+
+// SearchCompanies searches companies by name. The requests are cancelled with ctx.
+func (s *SearchService) SearchCompanies(ctx context.Context, query, sessionCookie, proxyURL string) ([]SearchResult, error) {
+	client := s.scraper.HTTPClient(proxyURL)
 
-	csrfToken, jsessionidCookie, err := acquireCsrfToken(sessionCookie, client)
+	csrfToken, jsessionidCookie, err := s.acquireCsrfToken(ctx, sessionCookie, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire CSRF token: %w", err)
 	}
 
-	apiResponse, err := callSearchAPI(query, csrfToken, sessionCookie, jsessionidCookie, client)
+	apiResponse, err := s.callSearchAPI(ctx, query, csrfToken, sessionCookie, jsessionidCookie, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call LinkedIn search API: %w", err)
 	}
@@ -34,26 +52,21 @@ func SearchCompanies(query, sessionCookie string) ([]SearchResult, error) {
 	return parseSearchResults(apiResponse)
 }
 
-func acquireCsrfToken(sessionCookie string, client *req.Client) (string, *http.Cookie, error) {
-	log.Println("Attempting to acquire CSRF token via /feed/")
+func (s *SearchService) acquireCsrfToken(ctx context.Context, sessionCookie string, client *req.Client) (string, *http.Cookie, error) {
+	s.logger.Println("Attempting to acquire CSRF token via /feed/")
 
-	resp, err := client.R().
-		SetCookies(&http.Cookie{
-			Name:  "li_at",
-			Value: sessionCookie,
-		}).
-		Get("https://www.linkedin.com/feed/")
+	resp, attempts, err := s.scraper.Get(ctx, client, "https://www.linkedin.com/feed/", sessionCookie, nil)
 	if err != nil {
-		return "", nil, fmt.Errorf("priming request failed: %w", err)
+		return "", nil, scraper.WrapRequestError("priming request failed", attempts, err)
 	}
 	if !resp.IsSuccessState() {
-		return "", nil, fmt.Errorf("priming request returned status: %d", resp.StatusCode)
+		return "", nil, fmt.Errorf("priming request failed: %w", &scraper.StatusError{StatusCode: resp.StatusCode, Attempts: attempts})
 	}
 
 	for _, cookie := range resp.Cookies() {
 		if cookie.Name == "JSESSIONID" {
 			csrfToken := strings.Trim(cookie.Value, "\"")
-			log.Printf("CSRF token acquired: %s", csrfToken)
+			s.logger.Printf("CSRF token acquired: %s", csrfToken)
 			return csrfToken, cookie, nil
 		}
 	}
@@ -61,36 +74,30 @@ func acquireCsrfToken(sessionCookie string, client *req.Client) (string, *http.C
 	return "", nil, fmt.Errorf("JSESSIONID cookie not found")
 }
 
-func callSearchAPI(query, csrfToken, sessionCookie string, jsessionidCookie *http.Cookie, client *req.Client) ([]byte, error) {
+func (s *SearchService) callSearchAPI(ctx context.Context, query, csrfToken, sessionCookie string, jsessionidCookie *http.Cookie, client *req.Client) ([]byte, error) {
 	variables := fmt.Sprintf("(query:%s)", query)
-	log.Printf("Query variables: %s", variables)
+	s.logger.Printf("Query variables: %s", variables)
 
 	apiURL := fmt.Sprintf(
 		"https://www.linkedin.com/voyager/api/graphql?includeWebMetadata=true&variables=%s&queryId=voyagerSearchDashTypeahead.fa9acbcb761f7b5ec2c808e6da796296",
 		variables,
 	)
 
-	liAtCookie := &http.Cookie{
-		Name:  "li_at",
-		Value: sessionCookie,
-	}
-
-	log.Printf("Adding cookies: li_at=%s, JSESSIONID=%s", liAtCookie.Value, jsessionidCookie.Value)
+	s.logger.Printf("Adding cookies: li_at=%s, JSESSIONID=%s", sessionCookie, jsessionidCookie.Value)
 
-	resp, err := client.R().
-		SetHeaders(map[string]string{
+	resp, attempts, err := s.scraper.Get(ctx, client, apiURL, sessionCookie, func(r *req.Request) {
+		r.SetHeaders(map[string]string{
 			"accept":     "application/vnd.linkedin.normalized+json+2.1",
 			"csrf-token": csrfToken,
-		}).
-		SetCookies(liAtCookie, jsessionidCookie).
-		Get(apiURL)
+		}).SetCookies(jsessionidCookie)
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
+		return nil, scraper.WrapRequestError("search request failed", attempts, err)
 	}
 	if !resp.IsSuccessState() {
-		log.Printf("Search API response: %s", resp.String())
-		return nil, fmt.Errorf("search request failed: %d, content: %s", resp.StatusCode, resp.String())
+		s.logger.Printf("Search API response: %s", resp.String())
+		return nil, fmt.Errorf("search request failed: %w, content: %s", &scraper.StatusError{StatusCode: resp.StatusCode, Attempts: attempts}, resp.String())
 	}
 
 	return resp.Bytes(), nil
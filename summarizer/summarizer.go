@@ -2,13 +2,39 @@ package summarizer
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/vit0-9/li-enricher-api/parser"
 	"github.com/vit0-9/li-enricher-api/utils"
 )
 
-// CreateSummary transforms the raw data map into a structured summary.
-func CreateSummary(data map[string]interface{}) (map[string]interface{}, error) {
+// Fields are the fields of a company summary in the order they are presented, e.g. as
+// the default columns of the exports.
+var Fields = []string{
+	"name",
+	"linkedin_handle",
+	"linkedin_profile_url",
+	"external_id",
+	"website",
+	"tagline",
+	"description",
+	"founded_year",
+	"specialities",
+	"company_type",
+	"stock",
+	"employee_count_range",
+	"headquarters",
+	"office_locations",
+	"funding_summary",
+	"phone_numbers",
+	"similar_companies",
+}
+
+// CreateSummary transforms the raw data map into a structured summary. The more
+// expensive sections are only built when selected by fields (a nil FieldSet selects all).
+func CreateSummary(data map[string]interface{}, fields utils.FieldSet) (map[string]interface{}, error) {
 	// The raw JSON has an 'included' array. We need to find the company object within it.
 	included, ok := data["included"].([]interface{})
 	if !ok {
@@ -35,7 +61,7 @@ func CreateSummary(data map[string]interface{}) (map[string]interface{}, error)
 	summary["linkedin_handle"] = utils.SafeGetString(companyData, "universalName")
 	summary["linkedin_profile_url"] = utils.SafeGetString(companyData, "url")
 	summary["external_id"] = utils.SafeGetString(companyData, "entityUrn")
-	summary["website"] = utils.SafeGetString(companyData, "websiteUrl")
+	summary["website"] = utils.NormalizeURL(utils.SafeGetString(companyData, "websiteUrl"))
 	summary["tagline"] = utils.SafeGetString(companyData, "tagline")
 	summary["description"] = utils.SafeGetString(companyData, "description")
 
@@ -49,6 +75,13 @@ func CreateSummary(data map[string]interface{}) (map[string]interface{}, error)
 		summary["specialities"] = specialities
 	}
 
+	if companyType := extractCompanyType(companyData); companyType != "" {
+		summary["company_type"] = companyType
+	}
+	if stock := extractStock(companyData); stock != nil {
+		summary["stock"] = stock
+	}
+
 	// Safely extract employee count range
 	if empRange, ok := utils.SafeGet(companyData, "employeeCountRange").(map[string]interface{}); ok {
 		start, startOk := empRange["start"].(float64)
@@ -58,18 +91,107 @@ func CreateSummary(data map[string]interface{}) (map[string]interface{}, error)
 		}
 	}
 
-	// The rest of the logic can be added here following the same pattern:
-	// - Extract logo URL
-	// - Extract office locations
-	// - Extract funding summary
-	// For brevity, these are left as an exercise but would follow the same utils.SafeGet/utils.SafeGetString pattern.
-	summary["headquarters"] = extractHeadquarters(companyData)
-	summary["office_locations"] = extractOfficeLocations(companyData)
-	summary["funding_summary"] = extractFundingSummary(companyData)
+	if fields.Includes("headquarters") {
+		summary["headquarters"] = extractHeadquarters(companyData)
+	}
+	if fields.Includes("office_locations") {
+		summary["office_locations"] = extractOfficeLocations(companyData)
+	}
+	if fields.Includes("funding_summary") {
+		summary["funding_summary"] = extractFundingSummary(companyData)
+	}
+	if fields.Includes("phone_numbers") {
+		summary["phone_numbers"] = extractPhoneNumbers(companyData)
+	}
+	if fields.Includes("similar_companies") {
+		summary["similar_companies"] = parser.ExtractSimilarCompanies(data)
+	}
 
 	return summary, nil
 }
 
+// extractCompanyType reads the company type, which is either a {code, localizedName}
+// object or a plain code, and normalizes it.
+func extractCompanyType(companyData map[string]interface{}) string {
+	if code := utils.SafeGetString(companyData, "companyType", "code"); code != "" {
+		return utils.NormalizeCompanyType(code)
+	}
+	if name := utils.SafeGetString(companyData, "companyType", "localizedName"); name != "" {
+		return utils.NormalizeCompanyType(name)
+	}
+	return utils.NormalizeCompanyType(utils.SafeGetString(companyData, "companyType"))
+}
+
+// extractStock returns the symbol and exchange of a public company, read from the
+// stock quote LinkedIn embeds for listed companies.
+func extractStock(companyData map[string]interface{}) map[string]interface{} {
+	quote, ok := companyData["stockQuote"].(map[string]interface{})
+	if !ok {
+		if quotes, ok := companyData["stockQuotes"].([]interface{}); ok && len(quotes) > 0 {
+			quote, _ = quotes[0].(map[string]interface{})
+		}
+	}
+	if quote == nil {
+		return nil
+	}
+
+	symbol := utils.SafeGetString(quote, "stockSymbol")
+	if symbol == "" {
+		symbol = utils.SafeGetString(quote, "symbol")
+	}
+	if symbol == "" {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"symbol":   strings.ToUpper(symbol),
+		"exchange": strings.ToUpper(utils.SafeGetString(quote, "stockExchange")),
+	}
+}
+
+// extractPhoneNumbers reads the company phone (a string or a {number} object) and any
+// additional contact numbers, normalized to E.164 with the headquarters country as
+// the default region.
+func extractPhoneNumbers(companyData map[string]interface{}) []utils.PhoneNumber {
+	phones := []utils.PhoneNumber{}
+	defaultRegion := utils.SafeGetString(companyData, "headquarter", "address", "country")
+
+	var raws []string
+	collect := func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			raws = append(raws, v)
+		case map[string]interface{}:
+			if number := utils.SafeGetString(v, "number"); number != "" {
+				raws = append(raws, number)
+			}
+		}
+	}
+	collect(companyData["phone"])
+	for _, key := range []string{"phoneNumbers", "contactPhoneNumbers"} {
+		if list, ok := companyData[key].([]interface{}); ok {
+			for _, item := range list {
+				collect(item)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, raw := range raws {
+		phone := utils.NormalizePhoneNumber(raw, defaultRegion)
+		key := phone.E164
+		if key == "" {
+			key = phone.Raw
+		}
+		if phone.Raw == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		phones = append(phones, phone)
+	}
+	return phones
+}
+
 func extractHeadquarters(companyData map[string]interface{}) map[string]interface{} {
 	hqData, ok := utils.SafeGet(companyData, "headquarter").(map[string]interface{})
 	if !ok {
@@ -82,13 +204,17 @@ func extractHeadquarters(companyData map[string]interface{}) map[string]interfac
 
 	return map[string]interface{}{
 		"is_headquarters": true,
+		"line1":           utils.SafeGetString(address, "line1"),
+		"line2":           utils.SafeGetString(address, "line2"),
 		"city":            utils.SafeGetString(address, "city"),
 		"state":           utils.SafeGetString(address, "geographicArea"),
-		"country":         utils.SafeGetString(address, "country"),
+		"country":         strings.ToUpper(utils.SafeGetString(address, "country")),
 		"postal_code":     utils.SafeGetString(address, "postalCode"),
 	}
 }
 
+// extractOfficeLocations returns every location of every groupedLocations entry,
+// deduplicated by address since LinkedIn may list the same office in several groups.
 func extractOfficeLocations(companyData map[string]interface{}) []map[string]interface{} {
 	locations := []map[string]interface{}{}
 	groupedLocations, ok := utils.SafeGet(companyData, "groupedLocations").([]interface{})
@@ -96,27 +222,88 @@ func extractOfficeLocations(companyData map[string]interface{}) []map[string]int
 		return locations
 	}
 
+	seen := map[string]int{}
 	for _, locGroup := range groupedLocations {
-		if lg, ok := locGroup.(map[string]interface{}); ok {
-			if locs, ok := lg["locations"].([]interface{}); ok && len(locs) > 0 {
-				if locDetail, ok := locs[0].(map[string]interface{}); ok {
-					if address, ok := locDetail["address"].(map[string]interface{}); ok {
-						office := map[string]interface{}{
-							"is_headquarters": locDetail["headquarter"],
-							"city":            address["city"],
-							"state":           address["geographicArea"],
-							"country":         address["country"],
-							"postal_code":     address["postalCode"],
-						}
-						locations = append(locations, office)
-					}
+		lg, ok := locGroup.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		locs, ok := lg["locations"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, loc := range locs {
+			locDetail, ok := loc.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			office := extractOfficeLocation(locDetail, lg)
+			if office == nil {
+				continue
+			}
+
+			key := locationKey(office)
+			if idx, ok := seen[key]; ok {
+				// Keep the headquarters flag if any of the duplicates carries it.
+				if office["is_headquarters"] == true {
+					locations[idx]["is_headquarters"] = true
 				}
+				continue
 			}
+			seen[key] = len(locations)
+			locations = append(locations, office)
 		}
 	}
 	return locations
 }
 
+func extractOfficeLocation(locDetail, group map[string]interface{}) map[string]interface{} {
+	address, ok := locDetail["address"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	isHeadquarters, _ := locDetail["headquarter"].(bool)
+	office := map[string]interface{}{
+		"is_headquarters": isHeadquarters,
+		"line1":           utils.SafeGetString(address, "line1"),
+		"line2":           utils.SafeGetString(address, "line2"),
+		"city":            utils.SafeGetString(address, "city"),
+		"state":           utils.SafeGetString(address, "geographicArea"),
+		"country":         strings.ToUpper(utils.SafeGetString(address, "country")),
+		"postal_code":     utils.SafeGetString(address, "postalCode"),
+	}
+
+	if description := utils.SafeGetString(locDetail, "description"); description != "" {
+		office["description"] = description
+	}
+
+	// Coordinates are set per location, or only on the group for single-office cities.
+	latLong, ok := locDetail["latLong"].(map[string]interface{})
+	if !ok {
+		latLong, ok = group["latLong"].(map[string]interface{})
+	}
+	if ok {
+		latitude, latOk := latLong["latitude"].(float64)
+		longitude, lngOk := latLong["longitude"].(float64)
+		if latOk && lngOk {
+			office["latitude"] = latitude
+			office["longitude"] = longitude
+		}
+	}
+
+	return office
+}
+
+func locationKey(office map[string]interface{}) string {
+	parts := make([]string, 0, 6)
+	for _, field := range []string{"line1", "line2", "city", "state", "postal_code", "country"} {
+		value, _ := office[field].(string)
+		parts = append(parts, strings.ToLower(strings.TrimSpace(value)))
+	}
+	return strings.Join(parts, "|")
+}
+
 func extractFundingSummary(companyData map[string]interface{}) map[string]interface{} {
 	fundingData, ok := utils.SafeGet(companyData, "crunchbaseFundingData").(map[string]interface{})
 	if !ok {
@@ -134,18 +321,104 @@ func extractFundingSummary(companyData map[string]interface{}) map[string]interf
 	}
 
 	if lastRound, ok := fundingData["lastFundingRound"].(map[string]interface{}); ok {
-		lrSummary := map[string]interface{}{
-			"type": lastRound["localizedFundingType"],
-		}
-		if announcedOn, ok := lastRound["announcedOn"].(map[string]interface{}); ok {
-			year, yOk := announcedOn["year"].(float64)
-			month, mOk := announcedOn["month"].(float64)
-			day, dOk := announcedOn["day"].(float64)
-			if yOk && mOk && dOk {
-				lrSummary["announced_on"] = fmt.Sprintf("%d-%02d-%02d", int(year), int(month), int(day))
+		summary["last_round"] = extractFundingRound(lastRound)
+	}
+
+	if count, ok := fundingData["numberOfInvestors"].(float64); ok {
+		summary["investor_count"] = int(count)
+	} else if lastRound, ok := summary["last_round"].(map[string]interface{}); ok {
+		if count, ok := lastRound["investor_count"]; ok {
+			summary["investor_count"] = count
+		}
+	}
+
+	// The full round list is only embedded in some authenticated pages.
+	if rounds, ok := fundingData["fundingRounds"].([]interface{}); ok {
+		roundSummaries := []map[string]interface{}{}
+		for _, round := range rounds {
+			if r, ok := round.(map[string]interface{}); ok {
+				roundSummaries = append(roundSummaries, extractFundingRound(r))
 			}
 		}
-		summary["last_round"] = lrSummary
+		summary["rounds"] = roundSummaries
 	}
 	return summary
 }
+
+func extractFundingRound(round map[string]interface{}) map[string]interface{} {
+	roundSummary := map[string]interface{}{
+		"type": round["localizedFundingType"],
+	}
+	if fundingType := utils.SafeGetString(round, "fundingType"); fundingType != "" {
+		roundSummary["type_code"] = fundingType
+	}
+	if announcedOn, ok := round["announcedOn"].(map[string]interface{}); ok {
+		year, yOk := announcedOn["year"].(float64)
+		month, mOk := announcedOn["month"].(float64)
+		day, dOk := announcedOn["day"].(float64)
+		if yOk && mOk && dOk {
+			roundSummary["announced_on"] = fmt.Sprintf("%d-%02d-%02d", int(year), int(month), int(day))
+		}
+	}
+	if moneyRaised := extractMoneyAmount(round["moneyRaised"]); moneyRaised != nil {
+		roundSummary["money_raised"] = moneyRaised
+	}
+	if url := utils.SafeGetString(round, "fundingRoundCrunchbaseUrl"); url != "" {
+		roundSummary["crunchbase_url"] = url
+	}
+
+	leadInvestors := []map[string]interface{}{}
+	if investors, ok := round["leadInvestors"].([]interface{}); ok {
+		for _, investor := range investors {
+			inv, ok := investor.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := utils.SafeGetString(inv, "name")
+			if name == "" {
+				name = utils.SafeGetString(inv, "name", "text")
+			}
+			leadInvestors = append(leadInvestors, map[string]interface{}{
+				"name":           name,
+				"crunchbase_url": utils.SafeGetString(inv, "investorCrunchbaseUrl"),
+			})
+		}
+	}
+	roundSummary["lead_investors"] = leadInvestors
+
+	if others, ok := round["numberOfOtherInvestors"].(float64); ok {
+		roundSummary["investor_count"] = len(leadInvestors) + int(others)
+	}
+	if url := utils.SafeGetString(round, "investorsCrunchbaseUrl"); url != "" {
+		roundSummary["investors_crunchbase_url"] = url
+	}
+	return roundSummary
+}
+
+// extractMoneyAmount reads a {amount, currencyCode} object. LinkedIn serializes the
+// amount as a string, so both strings and numbers are accepted.
+func extractMoneyAmount(value interface{}) map[string]interface{} {
+	money, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var amount float64
+	switch v := money["amount"].(type) {
+	case float64:
+		amount = v
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		amount = parsed
+	default:
+		return nil
+	}
+
+	return map[string]interface{}{
+		"amount":   amount,
+		"currency": utils.SafeGetString(money, "currencyCode"),
+	}
+}
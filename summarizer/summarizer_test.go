@@ -0,0 +1,202 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/vit0-9/li-enricher-api/parser"
+	"github.com/vit0-9/li-enricher-api/utils"
+)
+
+// companyJSON is the merged Voyager JSON of a company with one similar page, holding
+// every section of the summary.
+const companyJSON = `{
+	"included": [
+		{
+			"entityUrn": "urn:li:fsd_company:1",
+			"pageType": "COMPANY",
+			"name": "Acme",
+			"universalName": "acme",
+			"url": "https://www.linkedin.com/company/acme/",
+			"websiteUrl": "https://acme.example/",
+			"tagline": "Everything",
+			"description": "Makes everything.",
+			"foundedOn": {"year": 1949},
+			"specialities": ["Anvils", "Rockets"],
+			"companyType": {"code": "PUBLIC_COMPANY"},
+			"stockQuote": {"stockSymbol": "acme", "stockExchange": "nyse"},
+			"employeeCountRange": {"start": 51, "end": 200},
+			"headquarter": {"address": {"line1": "1 Desert Road", "city": "Phoenix", "country": "us"}},
+			"groupedLocations": [{"locations": [{"headquarter": true, "address": {"line1": "1 Desert Road", "city": "Phoenix", "country": "us"}}]}],
+			"crunchbaseFundingData": {"numberOfFundingRounds": 2, "lastFundingRound": {"localizedFundingType": "Series A", "moneyRaised": {"amount": "5000000", "currencyCode": "USD"}}},
+			"phone": {"number": "(602) 555-0100"},
+			"*similarOrganizations": ["urn:li:fsd_company:2"]
+		},
+		{
+			"entityUrn": "urn:li:fsd_company:2",
+			"pageType": "COMPANY",
+			"name": "Globex",
+			"universalName": "globex",
+			"followerCount": 1200
+		}
+	]
+}`
+
+func decodeCompany(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(companyJSON), &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSummaryHoldsExactlyTheFields(t *testing.T) {
+	summary, err := CreateSummary(decodeCompany(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for key := range summary {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	want := append([]string(nil), Fields...)
+	sort.Strings(want)
+	if len(keys) != len(want) {
+		t.Fatalf("summary fields = %q, want the listed Fields %q", keys, want)
+	}
+	for i := range keys {
+		if keys[i] != want[i] {
+			t.Fatalf("summary fields = %q, want the listed Fields %q", keys, want)
+		}
+	}
+}
+
+func TestSummarySimilarCompanies(t *testing.T) {
+	summary, err := CreateSummary(decodeCompany(t), utils.ParseFieldSet("name,similar_companies"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	similar, ok := summary["similar_companies"].([]parser.SimilarCompany)
+	if !ok || len(similar) != 1 {
+		t.Fatalf("similar_companies = %#v, want the one similar page", summary["similar_companies"])
+	}
+	if similar[0].Slug != "globex" || similar[0].FollowerCount != 1200 {
+		t.Errorf("similar company = %+v, want globex with 1200 followers", similar[0])
+	}
+
+	summary, err = CreateSummary(decodeCompany(t), utils.ParseFieldSet("name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := summary["similar_companies"]; ok {
+		t.Error("similar_companies was extracted without being selected")
+	}
+}
+
+func TestSummaryWithoutCompany(t *testing.T) {
+	for _, raw := range []string{`{}`, `{"included": [{"pageType": "SHOWCASE"}]}`} {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			t.Fatal(err)
+		}
+		if summary, err := CreateSummary(data, nil); err == nil {
+			t.Errorf("CreateSummary(%s) = %v, want an error", raw, summary)
+		}
+	}
+}
+
+func TestExtractMoneyAmount(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  map[string]interface{}
+	}{
+		{map[string]interface{}{"amount": "5000000", "currencyCode": "USD"}, map[string]interface{}{"amount": 5e6, "currency": "USD"}},
+		{map[string]interface{}{"amount": 2.5e6, "currencyCode": "EUR"}, map[string]interface{}{"amount": 2.5e6, "currency": "EUR"}},
+		{map[string]interface{}{"amount": "12.5"}, map[string]interface{}{"amount": 12.5, "currency": ""}},
+		{map[string]interface{}{"amount": "undisclosed", "currencyCode": "USD"}, nil},
+		{map[string]interface{}{"amount": true}, nil},
+		{map[string]interface{}{"currencyCode": "USD"}, nil},
+		{"5000000", nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := extractMoneyAmount(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("extractMoneyAmount(%#v) = %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExtractFundingSummary(t *testing.T) {
+	var company map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"crunchbaseFundingData": {
+			"numberOfFundingRounds": 2,
+			"updatedAt": 1700000000,
+			"lastFundingRound": {
+				"localizedFundingType": "Series A",
+				"fundingType": "SERIES_A",
+				"announcedOn": {"year": 2023, "month": 4, "day": 7},
+				"moneyRaised": {"amount": "5000000", "currencyCode": "USD"},
+				"leadInvestors": [{"name": {"text": "Wile Ventures"}, "investorCrunchbaseUrl": "https://www.crunchbase.com/organization/wile"}, "broken"],
+				"numberOfOtherInvestors": 3
+			}
+		}
+	}`), &company); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := extractFundingSummary(company)
+	if summary["data_last_updated_utc"] != "2023-11-14T22:13:20Z" || summary["investor_count"] != 4 {
+		t.Errorf("funding summary = %#v, want the update time and 4 investors", summary)
+	}
+	round, _ := summary["last_round"].(map[string]interface{})
+	want := map[string]interface{}{
+		"type":           "Series A",
+		"type_code":      "SERIES_A",
+		"announced_on":   "2023-04-07",
+		"money_raised":   map[string]interface{}{"amount": 5e6, "currency": "USD"},
+		"lead_investors": []map[string]interface{}{{"name": "Wile Ventures", "crunchbase_url": "https://www.crunchbase.com/organization/wile"}},
+		"investor_count": 4,
+	}
+	if !reflect.DeepEqual(round, want) {
+		t.Errorf("last round = %#v, want %#v", round, want)
+	}
+	if _, ok := summary["rounds"]; ok {
+		t.Error("rounds listed without a fundingRounds array")
+	}
+
+	if summary := extractFundingSummary(map[string]interface{}{}); summary != nil {
+		t.Errorf("funding summary without Crunchbase data = %#v, want nil", summary)
+	}
+}
+
+func TestExtractPhoneNumbers(t *testing.T) {
+	var company map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"headquarter": {"address": {"country": "fr"}},
+		"phone": "01 23 45 67 89",
+		"phoneNumbers": [{"number": "+33 1 23 45 67 89"}, {"number": ""}, 42],
+		"contactPhoneNumbers": ["+1 602 555 0100", "call us"]
+	}`), &company); err != nil {
+		t.Fatal(err)
+	}
+
+	phones := extractPhoneNumbers(company)
+	want := []utils.PhoneNumber{
+		{Raw: "01 23 45 67 89", E164: "+33123456789", Country: "FR", Valid: true},
+		{Raw: "+1 602 555 0100", E164: "+16025550100", Country: "US", Valid: true},
+		{Raw: "call us"},
+	}
+	if !reflect.DeepEqual(phones, want) {
+		t.Errorf("phone numbers = %+v, want %+v", phones, want)
+	}
+
+	if phones := extractPhoneNumbers(map[string]interface{}{}); phones == nil || len(phones) != 0 {
+		t.Errorf("phone numbers of a company without any = %#v, want an empty list", phones)
+	}
+}
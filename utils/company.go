@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+)
+
+// Normalized company types. LinkedIn reports them as codes (PUBLIC_COMPANY) in the
+// Voyager JSON and as localized labels ("Public Company") on the guest page.
+const (
+	CompanyTypePublic           = "public_company"
+	CompanyTypePrivatelyHeld    = "privately_held"
+	CompanyTypeNonProfit        = "non_profit"
+	CompanyTypeGovernmentAgency = "government_agency"
+	CompanyTypeEducational      = "educational"
+	CompanyTypeSelfEmployed     = "self_employed"
+	CompanyTypeSelfOwned        = "self_owned"
+	CompanyTypePartnership      = "partnership"
+)
+
+var companyTypes = map[string]string{
+	"publiccompany":          CompanyTypePublic,
+	"public":                 CompanyTypePublic,
+	"privatelyheld":          CompanyTypePrivatelyHeld,
+	"private":                CompanyTypePrivatelyHeld,
+	"nonprofit":              CompanyTypeNonProfit,
+	"governmentagency":       CompanyTypeGovernmentAgency,
+	"government":             CompanyTypeGovernmentAgency,
+	"educational":            CompanyTypeEducational,
+	"educationalinstitution": CompanyTypeEducational,
+	"selfemployed":           CompanyTypeSelfEmployed,
+	"selfowned":              CompanyTypeSelfOwned,
+	"partnership":            CompanyTypePartnership,
+}
+
+// NormalizeCompanyType maps a LinkedIn company type code or label to one of the
+// CompanyType constants. Unknown values yield "".
+func NormalizeCompanyType(value string) string {
+	key := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(value)))
+	return companyTypes[key]
+}
+
+// ParseTickerSymbol splits tickers written as "NASDAQ: GOOG" or "GOOG" into exchange and symbol.
+func ParseTickerSymbol(value string) (exchange, symbol string) {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, ":"); i >= 0 {
+		return strings.ToUpper(strings.TrimSpace(value[:i])), strings.ToUpper(strings.TrimSpace(value[i+1:]))
+	}
+	return "", strings.ToUpper(value)
+}
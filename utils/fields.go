@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FieldSet is a sparse fieldset parsed from a `fields` query parameter such as
+// "name,website,headquarters.country". Each key maps to the selection of its nested
+// fields; a nil selection keeps the whole value. A nil FieldSet selects everything.
+type FieldSet map[string]FieldSet
+
+// ParseFieldSet parses a comma separated list of dot separated field paths.
+// An empty list yields a nil FieldSet.
+func ParseFieldSet(value string) FieldSet {
+	var fields FieldSet
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if fields == nil {
+			fields = FieldSet{}
+		}
+		fields.add(strings.Split(path, "."))
+	}
+	return fields
+}
+
+func (f FieldSet) add(path []string) {
+	head := strings.TrimSpace(path[0])
+	if head == "" {
+		return
+	}
+	sub, exists := f[head]
+	if len(path) == 1 {
+		// Selecting the whole field overrides any nested selection.
+		f[head] = nil
+		return
+	}
+	if exists && sub == nil {
+		return
+	}
+	if sub == nil {
+		sub = FieldSet{}
+		f[head] = sub
+	}
+	sub.add(path[1:])
+}
+
+// Includes reports whether the top-level field is selected.
+func (f FieldSet) Includes(field string) bool {
+	if f == nil {
+		return true
+	}
+	_, ok := f[field]
+	return ok
+}
+
+// Apply returns the selected fields of data. The value is converted to its JSON form
+// first, so structs are projected by their JSON field names. Lists are projected
+// element by element.
+func (f FieldSet) Apply(data interface{}) (interface{}, error) {
+	if f == nil || data == nil {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	return f.project(generic), nil
+}
+
+func (f FieldSet) project(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			projected = append(projected, f.project(item))
+		}
+		return projected
+	case map[string]interface{}:
+		projected := make(map[string]interface{}, len(f))
+		for key, sub := range f {
+			fieldValue, ok := v[key]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				projected[key] = fieldValue
+			} else {
+				projected[key] = sub.project(fieldValue)
+			}
+		}
+		return projected
+	}
+	return value
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldSet(t *testing.T) {
+	tests := []struct {
+		value string
+		want  FieldSet
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"name", FieldSet{"name": nil}},
+		{" name , website ", FieldSet{"name": nil, "website": nil}},
+		{"headquarters.country", FieldSet{"headquarters": {"country": nil}}},
+		{"headquarters.country,headquarters.city", FieldSet{"headquarters": {"country": nil, "city": nil}}},
+		{"funding_summary.last_round.type", FieldSet{"funding_summary": {"last_round": {"type": nil}}}},
+		// The whole field wins over its nested selections, in either order.
+		{"headquarters.country,headquarters", FieldSet{"headquarters": nil}},
+		{"headquarters,headquarters.country", FieldSet{"headquarters": nil}},
+		{".name", FieldSet{}},
+		{"headquarters.", FieldSet{"headquarters": {}}},
+	}
+	for _, tt := range tests {
+		if got := ParseFieldSet(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFieldSet(%q) = %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFieldSetIncludes(t *testing.T) {
+	var all FieldSet
+	if !all.Includes("anything") {
+		t.Error("a nil FieldSet must select every field")
+	}
+	fields := ParseFieldSet("name,headquarters.country")
+	for field, want := range map[string]bool{"name": true, "headquarters": true, "country": false, "website": false} {
+		if got := fields.Includes(field); got != want {
+			t.Errorf("Includes(%q) = %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestFieldSetApply(t *testing.T) {
+	company := map[string]interface{}{
+		"name":    "Acme",
+		"website": "https://acme.example",
+		"headquarters": map[string]interface{}{
+			"city":    "Phoenix",
+			"country": "US",
+		},
+		"office_locations": []interface{}{
+			map[string]interface{}{"city": "Phoenix", "country": "US"},
+			map[string]interface{}{"city": "Paris", "country": "FR"},
+		},
+		"funding_summary": nil,
+	}
+
+	tests := []struct {
+		fields string
+		want   interface{}
+	}{
+		{"", company},
+		{"name", map[string]interface{}{"name": "Acme"}},
+		{"headquarters.country", map[string]interface{}{
+			"headquarters": map[string]interface{}{"country": "US"},
+		}},
+		{"office_locations.city", map[string]interface{}{
+			"office_locations": []interface{}{
+				map[string]interface{}{"city": "Phoenix"},
+				map[string]interface{}{"city": "Paris"},
+			},
+		}},
+		// Unknown fields, at the top or nested, are left out rather than refused.
+		{"name,nope", map[string]interface{}{"name": "Acme"}},
+		{"headquarters.nope", map[string]interface{}{"headquarters": map[string]interface{}{}}},
+		{"nope", map[string]interface{}{}},
+		// Nested selections of a scalar or a null keep the value as it is.
+		{"name.first", map[string]interface{}{"name": "Acme"}},
+		{"funding_summary.last_round", map[string]interface{}{"funding_summary": nil}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFieldSet(tt.fields).Apply(company)
+		if err != nil {
+			t.Errorf("Apply(%q): %v", tt.fields, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Apply(%q) = %#v, want %#v", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestFieldSetApplyProjectsStructsByJSONName(t *testing.T) {
+	type office struct {
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	data := []office{{"Phoenix", "US"}, {"Paris", "FR"}}
+
+	got, err := ParseFieldSet("country").Apply(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		map[string]interface{}{"country": "US"},
+		map[string]interface{}{"country": "FR"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %#v, want %#v", got, want)
+	}
+
+	if _, err := ParseFieldSet("name").Apply(func() {}); err == nil {
+		t.Error("Apply accepted a value without a JSON form")
+	}
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PhoneNumber is a phone number as found on the page and its E.164 form.
+type PhoneNumber struct {
+	Raw     string `json:"raw"`
+	E164    string `json:"e164,omitempty"`
+	Country string `json:"country,omitempty"`
+	Valid   bool   `json:"valid"`
+}
+
+// numberingPlan describes the parts of a country's numbering plan needed to build and
+// check E.164 numbers: the calling code, the national trunk prefix and the length range
+// of the national significant number.
+type numberingPlan struct {
+	callingCode string
+	trunkPrefix string
+	minLength   int
+	maxLength   int
+}
+
+// numberingPlans is an offline table of the numbering plans of the countries we see most.
+var numberingPlans = map[string]numberingPlan{
+	"US": {"1", "1", 10, 10},
+	"CA": {"1", "1", 10, 10},
+	"GB": {"44", "0", 9, 10},
+	"IE": {"353", "0", 7, 9},
+	"DE": {"49", "0", 6, 11},
+	"AT": {"43", "0", 4, 13},
+	"CH": {"41", "0", 9, 9},
+	"FR": {"33", "0", 9, 9},
+	"BE": {"32", "0", 8, 9},
+	"NL": {"31", "0", 9, 9},
+	"LU": {"352", "", 4, 11},
+	"ES": {"34", "", 9, 9},
+	"PT": {"351", "", 9, 9},
+	"IT": {"39", "", 6, 11},
+	"DK": {"45", "", 8, 8},
+	"SE": {"46", "0", 7, 9},
+	"NO": {"47", "", 8, 8},
+	"FI": {"358", "0", 5, 12},
+	"PL": {"48", "", 9, 9},
+	"CZ": {"420", "", 9, 9},
+	"HU": {"36", "06", 8, 9},
+	"RO": {"40", "0", 9, 9},
+	"GR": {"30", "", 10, 10},
+	"TR": {"90", "0", 10, 10},
+	"RU": {"7", "8", 10, 10},
+	"UA": {"380", "0", 9, 9},
+	"IL": {"972", "0", 8, 9},
+	"AE": {"971", "0", 8, 9},
+	"SA": {"966", "0", 8, 9},
+	"IN": {"91", "0", 10, 10},
+	"CN": {"86", "0", 7, 11},
+	"HK": {"852", "", 8, 8},
+	"SG": {"65", "", 8, 8},
+	"JP": {"81", "0", 9, 10},
+	"KR": {"82", "0", 8, 10},
+	"AU": {"61", "0", 9, 9},
+	"NZ": {"64", "0", 8, 10},
+	"BR": {"55", "0", 10, 11},
+	"MX": {"52", "", 10, 10},
+	"AR": {"54", "0", 10, 10},
+	"CL": {"56", "", 9, 9},
+	"CO": {"57", "", 8, 10},
+	"ZA": {"27", "0", 9, 9},
+	"NG": {"234", "0", 8, 10},
+	"EG": {"20", "0", 8, 10},
+	"KE": {"254", "0", 9, 9},
+}
+
+// callingCodeRegions maps calling codes back to a region. Codes shared by several
+// regions (+1, +7) map to the most common one.
+var callingCodeRegions = map[string]string{}
+
+func init() {
+	for region, plan := range numberingPlans {
+		if existing, ok := callingCodeRegions[plan.callingCode]; !ok || region < existing {
+			callingCodeRegions[plan.callingCode] = region
+		}
+	}
+	callingCodeRegions["1"] = "US"
+	callingCodeRegions["7"] = "RU"
+}
+
+var phoneExtensionPattern = regexp.MustCompile(`(?i)\s*(ext\.?|extension|x|#)\s*\d+\s*$`)
+
+// bracketedTrunkPattern matches the trunk prefix written in brackets after the calling
+// code, as in "+44 (0)20 7946 0000".
+var bracketedTrunkPattern = regexp.MustCompile(`\(\s*0\s*\)`)
+
+// NormalizePhoneNumber converts a phone number to E.164. Numbers without an international
+// prefix are read in defaultRegion (ISO 3166 alpha-2). Extensions and trunk prefixes
+// written after the calling code, as in "+44 (0)20", are dropped. Numbers
+// that cannot be attributed to a known numbering plan are returned without E.164 form.
+func NormalizePhoneNumber(raw, defaultRegion string) PhoneNumber {
+	number := PhoneNumber{Raw: strings.TrimSpace(raw)}
+	value := phoneExtensionPattern.ReplaceAllString(number.Raw, "")
+
+	international := strings.HasPrefix(strings.TrimSpace(value), "+")
+	if international {
+		value = bracketedTrunkPattern.ReplaceAllString(value, "")
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	defaultRegion = strings.ToUpper(strings.TrimSpace(defaultRegion))
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case strings.HasPrefix(digits, "011") && numberingPlans[defaultRegion].callingCode == "1":
+		digits, international = digits[3:], true
+	}
+
+	var region, national string
+	if international {
+		for length := 1; length <= 3 && length < len(digits); length++ {
+			if r, ok := callingCodeRegions[digits[:length]]; ok {
+				region, national = r, digits[length:]
+				break
+			}
+		}
+		// Keep the default region for codes it shares with others (e.g. +1 for CA).
+		if plan, ok := numberingPlans[defaultRegion]; ok && region != "" && plan.callingCode == numberingPlans[region].callingCode {
+			region = defaultRegion
+		}
+		// Drop a trunk prefix kept after the calling code ("+44 020 ..."), when the
+		// number is only valid without it.
+		if plan := numberingPlans[region]; plan.trunkPrefix != "" && strings.HasPrefix(national, plan.trunkPrefix) {
+			trimmed := len(national) - len(plan.trunkPrefix)
+			if len(national) > plan.maxLength && trimmed >= plan.minLength {
+				national = national[len(plan.trunkPrefix):]
+			}
+		}
+	} else if plan, ok := numberingPlans[defaultRegion]; ok {
+		region = defaultRegion
+		national = digits
+		if plan.trunkPrefix != "" && strings.HasPrefix(digits, plan.trunkPrefix) && len(digits)-len(plan.trunkPrefix) >= plan.minLength {
+			national = digits[len(plan.trunkPrefix):]
+		}
+	}
+
+	if region == "" || national == "" {
+		return number
+	}
+
+	plan := numberingPlans[region]
+	number.Country = region
+	number.E164 = "+" + plan.callingCode + national
+	number.Valid = len(national) >= plan.minLength && len(national) <= plan.maxLength
+	return number
+}
@@ -0,0 +1,32 @@
+package utils
+
+import "testing"
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		raw, region string
+		want        PhoneNumber
+	}{
+		{"(602) 555-0100", "us", PhoneNumber{Raw: "(602) 555-0100", E164: "+16025550100", Country: "US", Valid: true}},
+		{"1-602-555-0100", "US", PhoneNumber{Raw: "1-602-555-0100", E164: "+16025550100", Country: "US", Valid: true}},
+		{" +1 602 555 0100 ext. 12 ", "", PhoneNumber{Raw: "+1 602 555 0100 ext. 12", E164: "+16025550100", Country: "US", Valid: true}},
+		{"+1 416 555 0100", "CA", PhoneNumber{Raw: "+1 416 555 0100", E164: "+14165550100", Country: "CA", Valid: true}},
+		{"+44 (0)20 7946 0000", "", PhoneNumber{Raw: "+44 (0)20 7946 0000", E164: "+442079460000", Country: "GB", Valid: true}},
+		{"+44 020 7946 0000", "", PhoneNumber{Raw: "+44 020 7946 0000", E164: "+442079460000", Country: "GB", Valid: true}},
+		{"00 33 1 23 45 67 89", "", PhoneNumber{Raw: "00 33 1 23 45 67 89", E164: "+33123456789", Country: "FR", Valid: true}},
+		{"011 33 1 23 45 67 89", "US", PhoneNumber{Raw: "011 33 1 23 45 67 89", E164: "+33123456789", Country: "FR", Valid: true}},
+		{"01 23 45 67 89", "FR", PhoneNumber{Raw: "01 23 45 67 89", E164: "+33123456789", Country: "FR", Valid: true}},
+		// Too short for the plan: kept with its E.164 form, but not valid.
+		{"+33 1 23", "", PhoneNumber{Raw: "+33 1 23", E164: "+33123", Country: "FR", Valid: false}},
+		// No international prefix and no known default region.
+		{"555-0100", "", PhoneNumber{Raw: "555-0100"}},
+		{"555-0100", "XX", PhoneNumber{Raw: "555-0100"}},
+		{"call us", "US", PhoneNumber{Raw: "call us"}},
+		{"", "US", PhoneNumber{}},
+	}
+	for _, tt := range tests {
+		if got := NormalizePhoneNumber(tt.raw, tt.region); got != tt.want {
+			t.Errorf("NormalizePhoneNumber(%q, %q) = %+v, want %+v", tt.raw, tt.region, got, tt.want)
+		}
+	}
+}
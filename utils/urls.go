@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// UnwrapRedirectURL returns the target of LinkedIn's redirect-wrapped outbound links
+// (https://www.linkedin.com/redir/redirect?url=...). Other URLs are returned unchanged.
+func UnwrapRedirectURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || !IsLinkedInHost(u.Host) || !strings.HasPrefix(u.Path, "/redir/redirect") {
+		return rawURL
+	}
+	if target := u.Query().Get("url"); target != "" {
+		return target
+	}
+	return rawURL
+}
+
+// NormalizeURL unwraps LinkedIn redirects, adds a missing scheme, lowercases the host and
+// drops fragments, tracking parameters and a trailing slash. Invalid URLs yield "".
+func NormalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(UnwrapRedirectURL(rawURL))
+	if rawURL == "" {
+		return ""
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || !strings.Contains(u.Host, ".") {
+		return ""
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return u.String()
+}
+
+// IsLinkedInHost reports whether the host belongs to LinkedIn.
+func IsLinkedInHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com") || host == "lnkd.in"
+}
+
+// CompanySlugFromURL returns the slug of a LinkedIn company URL such as
+// https://www.linkedin.com/company/<slug>/about, or "" for other URLs, including
+// company paths on other hosts and relative URLs.
+func CompanySlugFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !IsLinkedInHost(u.Host) {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || (segments[0] != "company" && segments[0] != "showcase") {
+		return ""
+	}
+	return segments[1]
+}
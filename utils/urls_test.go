@@ -0,0 +1,26 @@
+package utils
+
+import "testing"
+
+func TestCompanySlugFromURL(t *testing.T) {
+	tests := map[string]string{
+		"https://www.linkedin.com/company/acme":               "acme",
+		"https://www.linkedin.com/company/acme/about/":        "acme",
+		"https://linkedin.com/company/1234?trk=similar-pages": "1234",
+		"https://fr.linkedin.com/showcase/acme-labs":          "acme-labs",
+		"HTTPS://WWW.LINKEDIN.COM/company/acme":               "acme",
+		"https://www.linkedin.com/in/someone":                 "",
+		"https://www.linkedin.com/company/":                   "",
+		"https://evil.example/company/foo":                    "",
+		"https://linkedin.com.evil.example/company/foo":       "",
+		"https://evillinkedin.com/company/foo":                "",
+		"/company/foo":                                        "",
+		"":                                                    "",
+		"://bad":                                              "",
+	}
+	for rawURL, want := range tests {
+		if got := CompanySlugFromURL(rawURL); got != want {
+			t.Errorf("CompanySlugFromURL(%q) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package watch
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoCookieKey is returned when a watchlist with a session cookie is saved to a store
+// that has no key to encrypt it with.
+var ErrNoCookieKey = errors.New("session cookies cannot be stored without a cookie encryption key")
+
+// encryptedCookiePrefix marks the stored cookies encrypted with AES-GCM, versioning
+// their format.
+const encryptedCookiePrefix = "enc:v1:"
+
+// cookieCipher encrypts the session cookies at rest.
+type cookieCipher struct {
+	aead cipher.AEAD
+}
+
+func newCookieCipher(key []byte) (*cookieCipher, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("cookie encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &cookieCipher{aead: aead}, nil
+}
+
+// encode returns the stored form of a session cookie.
+func (c *cookieCipher) encode(cookie string) (string, error) {
+	if cookie == "" {
+		return "", nil
+	}
+	if c == nil {
+		return "", ErrNoCookieKey
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt session cookie: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(cookie), nil)
+	return encryptedCookiePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decode returns the session cookie of its stored form.
+func (c *cookieCipher) decode(stored string) (string, error) {
+	if stored == "" {
+		return "", nil
+	}
+	encoded, encrypted := strings.CutPrefix(stored, encryptedCookiePrefix)
+	if !encrypted {
+		return "", fmt.Errorf("malformed encrypted session cookie")
+	}
+	if c == nil {
+		return "", ErrNoCookieKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted session cookie")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	cookie, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt session cookie, was the key changed? %w", err)
+	}
+	return string(cookie), nil
+}
@@ -0,0 +1,38 @@
+package watch
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCookieCipherRoundTrip(t *testing.T) {
+	cookies, err := newCookieCipher(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := cookies.encode("AQEDAR-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored, encryptedCookiePrefix) || strings.Contains(stored, "secret") {
+		t.Fatalf("stored cookie %q is not encrypted", stored)
+	}
+	if cookie, err := cookies.decode(stored); err != nil || cookie != "AQEDAR-secret" {
+		t.Errorf("decode = %q, %v, want the cookie", cookie, err)
+	}
+
+	if stored, err := cookies.encode(""); err != nil || stored != "" {
+		t.Errorf("encode(\"\") = %q, %v, want an empty cookie", stored, err)
+	}
+	if _, err := cookies.decode("AQEDAR-plaintext"); err == nil {
+		t.Error("decode accepted a cookie stored in plaintext")
+	}
+
+	var noKey *cookieCipher
+	if _, err := noKey.encode("AQEDAR-secret"); !errors.Is(err, ErrNoCookieKey) {
+		t.Errorf("encode without a key = %v, want ErrNoCookieKey", err)
+	}
+}
@@ -0,0 +1,107 @@
+package watch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping watchlists in memory.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	nextID        int64
+	watchlists    map[int64]Watchlist
+	lastSnapshots map[runKey]int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{watchlists: map[int64]Watchlist{}, lastSnapshots: map[runKey]int64{}}
+}
+
+func (s *MemoryStore) Create(watchlist *Watchlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	watchlist.ID = s.nextID
+	watchlist.CreatedAt = time.Now().UTC()
+	watchlist.UpdatedAt = watchlist.CreatedAt
+	s.watchlists[watchlist.ID] = copyWatchlist(*watchlist)
+	return nil
+}
+
+func (s *MemoryStore) Get(id int64) (*Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	watchlist, ok := s.watchlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	watchlist = copyWatchlist(watchlist)
+	return &watchlist, nil
+}
+
+func (s *MemoryStore) List() ([]Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	watchlists := make([]Watchlist, 0, len(s.watchlists))
+	for _, watchlist := range s.watchlists {
+		watchlists = append(watchlists, copyWatchlist(watchlist))
+	}
+	sort.Slice(watchlists, func(i, j int) bool { return watchlists[i].ID < watchlists[j].ID })
+	return watchlists, nil
+}
+
+func (s *MemoryStore) Update(watchlist *Watchlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.watchlists[watchlist.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	watchlist.CreatedAt = existing.CreatedAt
+	watchlist.UpdatedAt = time.Now().UTC()
+	s.watchlists[watchlist.ID] = copyWatchlist(*watchlist)
+	return nil
+}
+
+func (s *MemoryStore) Delete(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.watchlists[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.watchlists, id)
+	for key := range s.lastSnapshots {
+		if key.watchlistID == id {
+			delete(s.lastSnapshots, key)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) LastSnapshot(watchlistID int64, slug string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastSnapshots[runKey{watchlistID: watchlistID, slug: slug}], nil
+}
+
+func (s *MemoryStore) SetLastSnapshot(watchlistID int64, slug string, snapshotID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSnapshots[runKey{watchlistID: watchlistID, slug: slug}] = snapshotID
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func copyWatchlist(watchlist Watchlist) Watchlist {
+	watchlist.Slugs = append([]string(nil), watchlist.Slugs...)
+	return watchlist
+}
@@ -0,0 +1,303 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/history"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// Run statuses of a scheduled company.
+const (
+	StatusPending = "pending"
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+)
+
+// RunState is the schedule of one company of a watchlist.
+type RunState struct {
+	Slug        string     `json:"slug"`
+	NextRun     time.Time  `json:"next_run"`
+	LastRun     *time.Time `json:"last_run,omitempty"`
+	LastStatus  string     `json:"last_status"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastChanges int        `json:"last_changes"`
+
+	schedule string
+}
+
+type runKey struct {
+	watchlistID int64
+	slug        string
+}
+
+// Scheduler re-enriches the companies of the watchlists on their schedule, one at a time
+// and at most one LinkedIn request every minInterval, and emits change events to the
+// sinks when a company's fields changed since its previous snapshot.
+type Scheduler struct {
+	store       Store
+	service     *services.CompanyService
+	sinks       []Sink
+	minInterval time.Duration
+	tick        time.Duration
+
+	mu          sync.Mutex
+	runs        map[runKey]*RunState
+	lastRequest time.Time
+
+	stop   chan struct{}
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewScheduler creates a scheduler. Events are emitted to sinks and to the webhook of
+// the watchlist, if any. Changes are found by comparing snapshots, so the service must
+// record them: without history it returns services.ErrHistoryDisabled.
+func NewScheduler(store Store, service *services.CompanyService, sinks []Sink, minInterval time.Duration) (*Scheduler, error) {
+	if !service.HistoryEnabled() {
+		return nil, fmt.Errorf("watchlists need company snapshots: %w", services.ErrHistoryDisabled)
+	}
+	return &Scheduler{
+		store:       store,
+		service:     service,
+		sinks:       sinks,
+		minInterval: minInterval,
+		tick:        15 * time.Second,
+		runs:        map[runKey]*RunState{},
+	}, nil
+}
+
+// Start runs the scheduler in the background until Stop is called.
+func (s *Scheduler) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.tick)
+		defer ticker.Stop()
+
+		for {
+			s.runDue()
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	log.Println("Watchlist scheduler started.")
+}
+
+// Stop stops the scheduler, cancelling the LinkedIn requests of the current run, and
+// waits for it to return.
+func (s *Scheduler) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.cancel()
+	<-s.done
+}
+
+// Schedule returns the run state of every company of a watchlist.
+func (s *Scheduler) Schedule(watchlist *Watchlist) ([]RunState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]RunState, 0, len(watchlist.Slugs))
+	for _, slug := range watchlist.Slugs {
+		state, err := s.stateLocked(watchlist, slug, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, *state)
+	}
+	return states, nil
+}
+
+// stateLocked returns the run state of a company, (re)computing its next run when the
+// company is new or the schedule changed.
+func (s *Scheduler) stateLocked(watchlist *Watchlist, slug string, now time.Time) (*RunState, error) {
+	key := runKey{watchlistID: watchlist.ID, slug: slug}
+	state, ok := s.runs[key]
+	if ok && state.schedule == watchlist.Schedule {
+		return state, nil
+	}
+
+	schedule, err := ParseSchedule(watchlist.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule of watchlist %d: %w", watchlist.ID, err)
+	}
+	if !ok {
+		state = &RunState{Slug: slug, LastStatus: StatusPending}
+		s.runs[key] = state
+	}
+	state.schedule = watchlist.Schedule
+	state.NextRun = schedule.Next(now).UTC()
+	return state, nil
+}
+
+type dueRun struct {
+	watchlist Watchlist
+	slug      string
+}
+
+func (s *Scheduler) runDue() {
+	watchlists, err := s.store.List()
+	if err != nil {
+		log.Printf("Scheduler: failed to list watchlists: %v", err)
+		return
+	}
+
+	now := time.Now()
+	var due []dueRun
+	active := map[runKey]bool{}
+
+	s.mu.Lock()
+	for _, watchlist := range watchlists {
+		for _, slug := range watchlist.Slugs {
+			active[runKey{watchlistID: watchlist.ID, slug: slug}] = true
+			state, err := s.stateLocked(&watchlist, slug, now)
+			if err != nil {
+				log.Printf("Scheduler: %v", err)
+				continue
+			}
+			if !state.NextRun.After(now) {
+				due = append(due, dueRun{watchlist: watchlist, slug: slug})
+			}
+		}
+	}
+	// Forget companies removed from their watchlist and deleted watchlists.
+	for key := range s.runs {
+		if !active[key] {
+			delete(s.runs, key)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].slug < due[j].slug })
+	for _, run := range due {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		s.run(&run.watchlist, run.slug)
+	}
+}
+
+// run re-enriches one company and emits a change event when its fields changed.
+func (s *Scheduler) run(watchlist *Watchlist, slug string) {
+	if !s.waitForRateLimit() {
+		return
+	}
+
+	changeSet, err := s.refresh(watchlist, slug)
+
+	s.mu.Lock()
+	now := time.Now().UTC()
+	state, stateErr := s.stateLocked(watchlist, slug, now)
+	if stateErr == nil {
+		schedule, _ := ParseSchedule(watchlist.Schedule)
+		state.LastRun = &now
+		state.NextRun = schedule.Next(now).UTC()
+		state.LastStatus, state.LastError, state.LastChanges = StatusOK, "", 0
+		if err != nil {
+			state.LastStatus, state.LastError = StatusFailed, err.Error()
+		} else if changeSet != nil {
+			state.LastChanges = len(changeSet.Changes)
+		}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Scheduler: failed to refresh %s of watchlist %d: %v", slug, watchlist.ID, err)
+		return
+	}
+	if changeSet == nil || len(changeSet.Changes) == 0 {
+		return
+	}
+
+	event := &ChangeEvent{
+		Type:          "company.changed",
+		WatchlistID:   watchlist.ID,
+		WatchlistName: watchlist.Name,
+		Slug:          slug,
+		DetectedAt:    now,
+		ChangeSet:     changeSet,
+	}
+	sinks := s.sinks
+	if watchlist.WebhookURL != "" {
+		sinks = append(append([]Sink(nil), sinks...), NewPublicWebhookSink(watchlist.WebhookURL))
+	}
+	for _, sink := range sinks {
+		if err := sink.Emit(s.ctx, event); err != nil {
+			log.Printf("Scheduler: failed to emit change event for %s: %v", slug, err)
+		}
+	}
+}
+
+// refresh enriches the company, which stores a new snapshot, and compares it with the
+// snapshot of the watchlist's previous run of the company, so that enrichments made
+// outside the watchlist in between do not hide changes. The first run has nothing to
+// compare with, nor has a run whose scrape type differs from the previous one.
+func (s *Scheduler) refresh(watchlist *Watchlist, slug string) (*services.ChangeSet, error) {
+	result, err := s.service.EnrichCompanyData(s.ctx, slug, watchlist.SessionCookie, watchlist.ProxyURL, services.EnrichOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if result.Snapshot == nil {
+		return nil, fmt.Errorf("the snapshot of %s was not saved", slug)
+	}
+
+	previousID, err := s.store.LastSnapshot(watchlist.ID, slug)
+	if err != nil {
+		return nil, err
+	}
+	var changeSet *services.ChangeSet
+	if previousID != 0 {
+		changeSet, err = s.service.ChangesBetween(slug, previousID, result.Snapshot.ID)
+		switch {
+		case errors.Is(err, history.ErrNotFound):
+			changeSet = nil
+		case err != nil:
+			return nil, err
+		case changeSet.From.ScrapeType != changeSet.To.ScrapeType:
+			changeSet = nil
+		}
+	}
+	if err := s.store.SetLastSnapshot(watchlist.ID, slug, result.Snapshot.ID); err != nil {
+		return nil, err
+	}
+	return changeSet, nil
+}
+
+// waitForRateLimit waits until the next LinkedIn request may be sent. It returns false
+// when the scheduler was stopped in the meantime.
+func (s *Scheduler) waitForRateLimit() bool {
+	s.mu.Lock()
+	wait := time.Until(s.lastRequest.Add(s.minInterval))
+	s.mu.Unlock()
+
+	if wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-s.stop:
+			return false
+		}
+	}
+
+	s.mu.Lock()
+	s.lastRequest = time.Now()
+	s.mu.Unlock()
+	return true
+}
@@ -0,0 +1,181 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/vit0-9/li-enricher-api/services"
+)
+
+// ChangeEvent is emitted when a scheduled re-enrichment finds changed fields.
+type ChangeEvent struct {
+	Type          string              `json:"type"`
+	WatchlistID   int64               `json:"watchlist_id"`
+	WatchlistName string              `json:"watchlist_name"`
+	Slug          string              `json:"slug"`
+	DetectedAt    time.Time           `json:"detected_at"`
+	ChangeSet     *services.ChangeSet `json:"change_set"`
+}
+
+// Sink receives change events. Emit stops when ctx is cancelled.
+type Sink interface {
+	Emit(ctx context.Context, event *ChangeEvent) error
+}
+
+// WebhookSink posts change events as JSON to a URL.
+type WebhookSink struct {
+	url    string
+	client *req.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: req.C().SetTimeout(10 * time.Second),
+	}
+}
+
+// NewPublicWebhookSink is NewWebhookSink for URLs given by API users: it only connects
+// to public addresses, checked when connecting so that a host resolving to an internal
+// address later on, or a redirect to one, is refused as well.
+func NewPublicWebhookSink(url string) *WebhookSink {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrUnsafeWebhookURL, host)
+			}
+			return nil
+		},
+	}
+	return &WebhookSink{
+		url:    url,
+		client: req.C().SetTimeout(10 * time.Second).SetProxy(nil).SetDial(dialer.DialContext),
+	}
+}
+
+// ErrUnsafeWebhookURL is returned for webhook URLs pointing to loopback, private,
+// link-local or otherwise non-public addresses.
+var ErrUnsafeWebhookURL = errors.New("webhook URL must point to a public address")
+
+// ValidateWebhookURL checks that a webhook URL given by an API user is an http(s) URL
+// whose host resolves to public addresses only, so that the server cannot be made to
+// post to its internal network or to a cloud metadata endpoint.
+func ValidateWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid webhook URL %q", rawURL)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrUnsafeWebhookURL, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host %q: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrUnsafeWebhookURL, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// reservedPrefixes are the special-purpose ranges the predicates of netip.Addr miss.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This network".
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT.
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments.
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking.
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved, and the broadcast address.
+	netip.MustParsePrefix("::/96"),          // IPv4-compatible addresses, deprecated.
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64.
+	netip.MustParsePrefix("fec0::/10"),      // Site-local addresses, deprecated.
+}
+
+// nat64Prefix is the well-known NAT64 prefix, whose addresses reach the IPv4 address in
+// their last four bytes.
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// isPublicIP reports whether ip is a public unicast address. IPv4-mapped and NAT64
+// addresses are judged by the IPv4 address they reach.
+func isPublicIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	if nat64Prefix.Contains(addr) {
+		bytes := addr.As16()
+		addr = netip.AddrFrom4([4]byte(bytes[12:]))
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, event *ChangeEvent) error {
+	resp, err := s.client.R().SetContext(ctx).SetBodyJsonMarshal(event).Post(s.url)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	if !resp.IsSuccessState() {
+		return fmt.Errorf("webhook returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NDJSONSink appends change events to a file, one JSON object per line.
+type NDJSONSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewNDJSONSink(path string) *NDJSONSink {
+	return &NDJSONSink{path: path}
+}
+
+func (s *NDJSONSink) Emit(_ context.Context, event *ChangeEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"https://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://0.1.2.3/hook",
+		"http://100.64.0.1/hook",
+		"http://100.127.255.254/hook",
+		"http://[::ffff:10.0.0.1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[64:ff9b::a00:1]/hook",
+		"http://[64:ff9b::a9fe:a9fe]/hook",
+	} {
+		if err := ValidateWebhookURL(context.Background(), rawURL); !errors.Is(err, ErrUnsafeWebhookURL) {
+			t.Errorf("%s: got %v, want ErrUnsafeWebhookURL", rawURL, err)
+		}
+	}
+
+	for _, rawURL := range []string{"ftp://example.com/hook", "http:///hook", "not a url"} {
+		if err := ValidateWebhookURL(context.Background(), rawURL); err == nil {
+			t.Errorf("%s: accepted", rawURL)
+		}
+	}
+
+	if err := ValidateWebhookURL(context.Background(), "https://93.184.215.14/hook"); err != nil {
+		t.Errorf("public address refused: %v", err)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.215.14":          true,
+		"100.63.255.255":         true,
+		"100.128.0.1":            true,
+		"2606:2800:220:1::1":     true,
+		"::ffff:93.184.215.14":   true,
+		"64:ff9b::5db8:d70e":     true, // NAT64 form of 93.184.215.14.
+		"0.0.0.0":                false,
+		"0.255.255.255":          false,
+		"100.64.0.0":             false,
+		"100.127.255.255":        false,
+		"127.0.0.1":              false,
+		"10.1.2.3":               false,
+		"169.254.169.254":        false,
+		"192.0.0.170":            false,
+		"198.18.0.1":             false,
+		"255.255.255.255":        false,
+		"::":                     false,
+		"::1":                    false,
+		"::10.0.0.1":             false,
+		"::ffff:10.0.0.1":        false,
+		"::ffff:100.64.0.1":      false,
+		"::ffff:169.254.169.254": false,
+		"64:ff9b::7f00:1":        false, // NAT64 form of 127.0.0.1.
+		"64:ff9b::6440:1":        false, // NAT64 form of 100.64.0.1.
+		"64:ff9b:1::a00:1":       false,
+		"fd00::1":                false,
+		"fe80::1":                false,
+		"fec0::1":                false,
+		"ff02::1":                false,
+	}
+	for value, want := range tests {
+		if got := isPublicIP(net.ParseIP(value)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestPublicWebhookSinkRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := NewPublicWebhookSink(server.URL).Emit(ctx, &ChangeEvent{Type: "company.changed"})
+	if !errors.Is(err, ErrUnsafeWebhookURL) {
+		t.Errorf("got %v, want ErrUnsafeWebhookURL", err)
+	}
+	if called {
+		t.Error("the webhook on the loopback address was called")
+	}
+}
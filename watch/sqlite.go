@@ -0,0 +1,247 @@
+package watch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vit0-9/li-enricher-api/history"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS watchlists (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	name           TEXT    NOT NULL,
+	slugs          TEXT    NOT NULL,
+	schedule       TEXT    NOT NULL,
+	webhook_url    TEXT    NOT NULL,
+	session_cookie TEXT    NOT NULL,
+	proxy_url      TEXT    NOT NULL,
+	created_at     INTEGER NOT NULL,
+	updated_at     INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS watchlist_runs (
+	watchlist_id INTEGER NOT NULL,
+	slug         TEXT    NOT NULL,
+	snapshot_id  INTEGER NOT NULL,
+	PRIMARY KEY (watchlist_id, slug)
+);
+`
+
+// SQLiteStore is a Store backed by a SQLite database file. It can share the file of
+// the snapshot store. Session cookies are stored encrypted with the key set by
+// WithCookieKey; without a key, watchlists with a session cookie are refused.
+type SQLiteStore struct {
+	db      *sql.DB
+	cookies *cookieCipher
+}
+
+// SQLiteOption configures a SQLiteStore.
+type SQLiteOption func(*sqliteConfig)
+
+type sqliteConfig struct {
+	cookieKey []byte
+}
+
+// WithCookieKey encrypts the stored session cookies with AES-256-GCM under key, which
+// must be 32 bytes long.
+func WithCookieKey(key []byte) SQLiteOption {
+	return func(c *sqliteConfig) { c.cookieKey = key }
+}
+
+// NewSQLiteStore opens (and creates if needed) the SQLite database at path.
+func NewSQLiteStore(path string, opts ...SQLiteOption) (*SQLiteStore, error) {
+	var config sqliteConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	store := &SQLiteStore{}
+	if config.cookieKey != nil {
+		cookies, err := newCookieCipher(config.cookieKey)
+		if err != nil {
+			return nil, err
+		}
+		store.cookies = cookies
+	}
+
+	db, err := sql.Open("sqlite", history.SQLiteDSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open watchlist database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	store.db = db
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create watchlist schema: %w", err)
+	}
+	return store, nil
+}
+
+func (s *SQLiteStore) Create(watchlist *Watchlist) error {
+	slugs, err := json.Marshal(watchlist.Slugs)
+	if err != nil {
+		return err
+	}
+	cookie, err := s.cookies.encode(watchlist.SessionCookie)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	res, err := s.db.Exec(
+		`INSERT INTO watchlists (name, slugs, schedule, webhook_url, session_cookie, proxy_url, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		watchlist.Name, string(slugs), watchlist.Schedule, watchlist.WebhookURL,
+		cookie, watchlist.ProxyURL, now.UnixNano(), now.UnixNano(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create watchlist: %w", err)
+	}
+	watchlist.ID, err = res.LastInsertId()
+	watchlist.CreatedAt, watchlist.UpdatedAt = now, now
+	return err
+}
+
+func (s *SQLiteStore) Get(id int64) (*Watchlist, error) {
+	row := s.db.QueryRow(
+		`SELECT id, name, slugs, schedule, webhook_url, session_cookie, proxy_url, created_at, updated_at
+		 FROM watchlists WHERE id = ?`, id,
+	)
+	watchlist, err := s.scanWatchlist(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return watchlist, err
+}
+
+func (s *SQLiteStore) List() ([]Watchlist, error) {
+	rows, err := s.db.Query(
+		`SELECT id, name, slugs, schedule, webhook_url, session_cookie, proxy_url, created_at, updated_at
+		 FROM watchlists ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+	defer rows.Close()
+
+	watchlists := []Watchlist{}
+	for rows.Next() {
+		watchlist, err := s.scanWatchlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		watchlists = append(watchlists, *watchlist)
+	}
+	return watchlists, rows.Err()
+}
+
+func (s *SQLiteStore) Update(watchlist *Watchlist) error {
+	slugs, err := json.Marshal(watchlist.Slugs)
+	if err != nil {
+		return err
+	}
+	cookie, err := s.cookies.encode(watchlist.SessionCookie)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	res, err := s.db.Exec(
+		`UPDATE watchlists SET name = ?, slugs = ?, schedule = ?, webhook_url = ?, session_cookie = ?, proxy_url = ?, updated_at = ?
+		 WHERE id = ?`,
+		watchlist.Name, string(slugs), watchlist.Schedule, watchlist.WebhookURL,
+		cookie, watchlist.ProxyURL, now.UnixNano(), watchlist.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update watchlist: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	updated, err := s.Get(watchlist.ID)
+	if err != nil {
+		return err
+	}
+	*watchlist = *updated
+	return nil
+}
+
+func (s *SQLiteStore) Delete(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM watchlists WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	if _, err := s.db.Exec(`DELETE FROM watchlist_runs WHERE watchlist_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete the runs of watchlist %d: %w", id, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) LastSnapshot(watchlistID int64, slug string) (int64, error) {
+	var snapshotID int64
+	err := s.db.QueryRow(
+		`SELECT snapshot_id FROM watchlist_runs WHERE watchlist_id = ? AND slug = ?`, watchlistID, slug,
+	).Scan(&snapshotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the last run of %s: %w", slug, err)
+	}
+	return snapshotID, nil
+}
+
+func (s *SQLiteStore) SetLastSnapshot(watchlistID int64, slug string, snapshotID int64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO watchlist_runs (watchlist_id, slug, snapshot_id) VALUES (?, ?, ?)
+		 ON CONFLICT (watchlist_id, slug) DO UPDATE SET snapshot_id = excluded.snapshot_id`,
+		watchlistID, slug, snapshotID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record the last run of %s: %w", slug, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (s *SQLiteStore) scanWatchlist(row scanner) (*Watchlist, error) {
+	var watchlist Watchlist
+	var slugs string
+	var createdAt, updatedAt int64
+	err := row.Scan(
+		&watchlist.ID,
+		&watchlist.Name,
+		&slugs,
+		&watchlist.Schedule,
+		&watchlist.WebhookURL,
+		&watchlist.SessionCookie,
+		&watchlist.ProxyURL,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(slugs), &watchlist.Slugs); err != nil {
+		return nil, fmt.Errorf("failed to decode slugs of watchlist %d: %w", watchlist.ID, err)
+	}
+	if watchlist.SessionCookie, err = s.cookies.decode(watchlist.SessionCookie); err != nil {
+		return nil, fmt.Errorf("failed to read the session cookie of watchlist %d: %w", watchlist.ID, err)
+	}
+	watchlist.Authenticated = watchlist.SessionCookie != ""
+	watchlist.CreatedAt = time.Unix(0, createdAt).UTC()
+	watchlist.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return &watchlist, nil
+}
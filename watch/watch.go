@@ -0,0 +1,85 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/vit0-9/li-enricher-api/history"
+)
+
+// ErrNotFound is returned when a watchlist does not exist.
+var ErrNotFound = errors.New("watchlist not found")
+
+// Watchlist is a set of companies re-enriched on a cron schedule. The session cookie and
+// proxy are the ones given when the watchlist was saved, and are never returned.
+type Watchlist struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Slugs         []string  `json:"slugs"`
+	Schedule      string    `json:"schedule"`
+	WebhookURL    string    `json:"webhook_url,omitempty"`
+	Authenticated bool      `json:"authenticated"`
+	SessionCookie string    `json:"-"`
+	ProxyURL      string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Store persists watchlists.
+type Store interface {
+	Create(watchlist *Watchlist) error
+	Get(id int64) (*Watchlist, error)
+	List() ([]Watchlist, error)
+	Update(watchlist *Watchlist) error
+	Delete(id int64) error
+	// LastSnapshot returns the ID of the snapshot recorded by the last successful run of
+	// a company of a watchlist, or 0 when it never ran.
+	LastSnapshot(watchlistID int64, slug string) (int64, error)
+	SetLastSnapshot(watchlistID int64, slug string, snapshotID int64) error
+	Close() error
+}
+
+// ParseSchedule parses a standard 5-field cron expression or a descriptor such as
+// "@daily" or "@every 6h".
+func ParseSchedule(expression string) (cron.Schedule, error) {
+	return cron.ParseStandard(expression)
+}
+
+// Normalize cleans up the watchlist and checks it can be scheduled.
+func (w *Watchlist) Normalize() error {
+	w.Name = strings.TrimSpace(w.Name)
+	w.Schedule = strings.TrimSpace(w.Schedule)
+
+	seen := map[string]bool{}
+	slugs := []string{}
+	for _, slug := range w.Slugs {
+		slug = history.NormalizeSlug(slug)
+		if slug != "" && !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	w.Slugs = slugs
+	w.Authenticated = w.SessionCookie != ""
+
+	if w.Name == "" {
+		return fmt.Errorf("watchlist name cannot be empty")
+	}
+	if len(w.Slugs) == 0 {
+		return fmt.Errorf("watchlist must contain at least one company slug")
+	}
+	if _, err := ParseSchedule(w.Schedule); err != nil {
+		return fmt.Errorf("invalid cron schedule %q: %w", w.Schedule, err)
+	}
+	if w.WebhookURL != "" {
+		u, err := url.Parse(w.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", w.WebhookURL)
+		}
+	}
+	return nil
+}